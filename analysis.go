@@ -0,0 +1,268 @@
+package go_carcassonne
+
+import (
+	"math"
+
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+)
+
+// Hole is an empty space that must be filled to complete a structure
+type Hole struct {
+	X, Y       int
+	Fits       int  // number of tiles left that fit the space in some rotation
+	Closes     int  // number of tiles left that fit the space without giving the structure a new open side
+	Impossible bool // no tile left fits the space so the structure can never be completed
+}
+
+// Completion estimates how likely an incomplete city, road, or cloister is to be completed before the game ends
+type Completion struct {
+	Type        string   // City, Road, or Cloister
+	X, Y        int      // location of a tile in the structure
+	Side        string   // side of the tile in the structure, empty for a cloister
+	Teams       []string // teams that would score the structure, empty if unclaimed
+	Points      int      // points the structure is worth if the game ended now
+	Holes       []*Hole
+	Impossible  bool    // at least one hole can never be filled
+	Probability float64 // estimated chance the structure is completed
+}
+
+// Completions estimates for every incomplete city, road, and cloister on the board how likely it is to be completed given the tiles left
+func (c *Carcassonne) Completions() ([]*Completion, error) {
+	if c.over() {
+		return []*Completion{}, nil
+	}
+	return c.state.completions()
+}
+
+// completions gets the completion of every incomplete city, road, and cloister in the order they are found on the board
+func (s *state) completions() ([]*Completion, error) {
+	counts, left := s.tilesLeft()
+	completions := make([]*Completion, 0)
+	visited := make(map[*tile]map[string]bool)
+	for _, t := range s.board.board {
+		for _, side := range Sides {
+			if visited[t][side] || (t.Sides[side] != City && t.Sides[side] != Road) {
+				continue
+			}
+			var structure *structure
+			var err error
+			if t.Sides[side] == City {
+				structure, err = s.board.generateCity(t.X, t.Y, side)
+			} else {
+				structure, err = s.board.generateRoad(t.X, t.Y, side)
+			}
+			if err != nil {
+				return nil, &bgerr.Error{
+					Err:    err,
+					Status: bgerr.StatusInvalidAction,
+				}
+			}
+			for _, n := range structure.nodes {
+				if visited[n.tile] == nil {
+					visited[n.tile] = make(map[string]bool)
+				}
+				for _, nodeSide := range n.sides {
+					visited[n.tile][nodeSide] = true
+				}
+			}
+			if structure.complete {
+				continue
+			}
+			points, err := scoreCity(structure)
+			if structure.typ == Road {
+				points, err = scoreRoad(structure)
+			}
+			if err != nil {
+				return nil, &bgerr.Error{
+					Err:    err,
+					Status: bgerr.StatusInvalidAction,
+				}
+			}
+			completion := &Completion{
+				Type:   structure.typ,
+				X:      t.X,
+				Y:      t.Y,
+				Side:   side,
+				Teams:  s.pointsWinners(tokensInStructure(s.boardTokens, structure)),
+				Points: points,
+				Holes:  s.structureHoles(structure, counts),
+			}
+			completion.estimate(left, s.turnsLeft(completion.Teams, left))
+			completions = append(completions, completion)
+		}
+		if t.Center == Cloister {
+			count, err := s.board.tilesSurroundingCloister(t.X, t.Y)
+			if err != nil {
+				return nil, &bgerr.Error{
+					Err:    err,
+					Status: bgerr.StatusInvalidAction,
+				}
+			}
+			if count == 8 {
+				continue
+			}
+			completion := &Completion{
+				Type:   Cloister,
+				X:      t.X,
+				Y:      t.Y,
+				Teams:  make([]string, 0),
+				Points: count + 1,
+				Holes:  s.cloisterHoles(t, counts),
+			}
+			for _, token := range s.boardTokens {
+				if token.Type == Monk && token.X == t.X && token.Y == t.Y {
+					completion.Teams = append(completion.Teams, token.Team)
+				}
+			}
+			completion.estimate(left, s.turnsLeft(completion.Teams, left))
+			completions = append(completions, completion)
+		}
+	}
+	return completions, nil
+}
+
+// tilesLeft gets the number of each type of tile yet to be placed and their total
+func (s *state) tilesLeft() ([]int, int) {
+	counts := tileCounts(s.board.placedTiles())
+	left := 0
+	for _, count := range counts {
+		left += count
+	}
+	return counts, left
+}
+
+// scoringProbability estimates the chance the city, road, or cloister of result is completed
+func (s *state) scoringProbability(result *scoring, counts []int, left int) float64 {
+	completion := &Completion{}
+	if result.typ == Cloister {
+		completion.Holes = s.cloisterHoles(result.tile, counts)
+	} else {
+		completion.Holes = s.structureHoles(result.structure, counts)
+	}
+	completion.estimate(left, s.turnsLeft(result.winners, left))
+	return completion.Probability
+}
+
+// turnsLeft gets the number of the left tiles that teams will draw assuming turns are shared evenly by the teams still playing
+// an unclaimed structure is assumed to be worked on by one team, whichever claims it
+func (s *state) turnsLeft(teams []string, left int) int {
+	playing := len(s.teams) - len(s.resigned)
+	if playing <= 0 {
+		return 0
+	}
+	working := min(max(len(teams), 1), playing)
+	return (left*working + playing - 1) / playing
+}
+
+// estimate sets the probability of completing the structure when turns of the left tiles are drawn for it at random
+// holes are treated as independent of each other
+func (c *Completion) estimate(left, turns int) {
+	c.Probability = 1
+	for _, hole := range c.Holes {
+		if hole.Impossible {
+			c.Impossible = true
+		}
+		c.Probability *= holeProbability(hole.Fits, hole.Closes, left, turns)
+	}
+}
+
+// holeProbability gets the chance a hole is closed when turns tiles are drawn without replacement from the left tiles
+// the hole is filled by the first drawn tile that fits it which closes the hole with a chance of closes out of fits
+// while any other fitting tile leaves the structure open
+func holeProbability(fits, closes, left, turns int) float64 {
+	if closes == 0 {
+		return 0
+	}
+	// chance none of the tiles that fit are drawn
+	missed := 1.
+	for i := 0; i < turns && i < left && missed > 0; i++ {
+		missed *= math.Max(float64(left-fits-i), 0) / float64(left-i)
+	}
+	return float64(closes) / float64(fits) * (1 - missed)
+}
+
+// structureHoles gets the empty spaces next to the open sides of a city or road structure
+func (s *state) structureHoles(structure *structure, counts []int) []*Hole {
+	holes := make([]*Hole, 0)
+	entries := make(map[[2]int][]string) // sides of each empty space the structure enters from
+	for _, n := range structure.nodes {
+		for _, side := range n.sides {
+			if n.tile.adjacent[side] != nil {
+				continue
+			}
+			x, y := neighbor(n.tile.X, n.tile.Y, side)
+			if _, ok := entries[[2]int{x, y}]; !ok {
+				holes = append(holes, &Hole{X: x, Y: y})
+			}
+			entries[[2]int{x, y}] = append(entries[[2]int{x, y}], AcrossSide[side])
+		}
+	}
+	for _, hole := range holes {
+		hole.fill(s.board.emptySpace(hole.X, hole.Y), structure.typ, entries[[2]int{hole.X, hole.Y}], counts)
+	}
+	return holes
+}
+
+// cloisterHoles gets the empty spaces surrounding a cloister
+func (s *state) cloisterHoles(cloister *tile, counts []int) []*Hole {
+	holes := make([]*Hole, 0)
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			x, y := cloister.X+dx, cloister.Y+dy
+			if s.board.tile(x, y) != nil {
+				continue
+			}
+			hole := &Hole{X: x, Y: y}
+			hole.fill(s.board.emptySpace(x, y), Cloister, nil, counts)
+			holes = append(holes, hole)
+		}
+	}
+	return holes
+}
+
+// fill counts the tiles left that fit the hole and those that close the structure entering the hole from entries
+func (h *Hole) fill(emptySpace *tile, typ string, entries []string, counts []int) {
+	for i := range tiles {
+		if counts[i] == 0 {
+			continue
+		}
+		fit, closes := false, false
+		for _, rotated := range rotations[i] {
+			if fits(emptySpace, rotated) {
+				fit = true
+				if closes = closesStructure(emptySpace, rotated, typ, entries); closes {
+					break
+				}
+			}
+		}
+		if fit {
+			h.Fits += counts[i]
+		}
+		if closes {
+			h.Closes += counts[i]
+		}
+	}
+	h.Impossible = h.Fits == 0
+}
+
+// closesStructure checks whether t placed in emptySpace leaves the structure entering from entries without a new open side
+func closesStructure(emptySpace, t *tile, typ string, entries []string) bool {
+	for _, entry := range entries {
+		var connected []string
+		var err error
+		if typ == City {
+			connected, err = t.connectedCitySides(entry)
+		} else {
+			connected, err = t.connectedRoadSides(entry)
+		}
+		if err != nil {
+			return false
+		}
+		for _, side := range connected {
+			if emptySpace.adjacent[side] == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
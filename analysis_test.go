@@ -0,0 +1,141 @@
+package go_carcassonne
+
+import (
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Completions(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 3},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	completions, err := carcassonne.Completions()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// the start tile has a city and a road
+	assert.Len(t, completions, 2)
+	city, road := completions[0], completions[1]
+	assert.Equal(t, City, city.Type)
+	assert.Equal(t, 1, city.Points)
+	assert.Len(t, city.Holes, 1)
+	assert.Equal(t, 0, city.Holes[0].X)
+	assert.Equal(t, 1, city.Holes[0].Y)
+	assert.Greater(t, city.Holes[0].Fits, city.Holes[0].Closes)
+	assert.Greater(t, city.Holes[0].Closes, 0)
+	assert.False(t, city.Impossible)
+	assert.Greater(t, city.Probability, 0.)
+	assert.Less(t, city.Probability, 1.)
+	assert.Equal(t, Road, road.Type)
+	assert.Len(t, road.Holes, 2)
+	// both ends of the road must be closed
+	_, left := carcassonne.state.tilesLeft()
+	turns := carcassonne.state.turnsLeft(road.Teams, left)
+	assert.Less(t, road.Probability, holeProbability(road.Holes[0].Fits, road.Holes[0].Closes, left, turns))
+}
+
+func Test_HoleProbability(t *testing.T) {
+	// a hole is closed by the first tile drawn that fits so drawing every tile left closes it with a chance of closes out of fits
+	assert.InDelta(t, 0.4, holeProbability(10, 4, 50, 50), 1e-9)
+	assert.Equal(t, 0., holeProbability(10, 0, 50, 50))
+	assert.Equal(t, 0., holeProbability(10, 4, 50, 0))
+
+	// the estimate drops as fewer tiles remain to be drawn even when the share of tiles that fit and close stays the same
+	s := newState([]string{TeamA, TeamB}, 1, nil)
+	previous := 1.
+	for _, left := range []int{64, 32, 16, 8} {
+		completion := &Completion{Holes: []*Hole{{Fits: left / 4, Closes: left / 8}}}
+		completion.estimate(left, s.turnsLeft(nil, left))
+		assert.Less(t, completion.Probability, previous)
+		previous = completion.Probability
+	}
+}
+
+func Test_Completions_Impossible(t *testing.T) {
+	s := deadSpaceState(t)
+	completions, err := s.completions()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	impossible := 0
+	for _, completion := range completions {
+		for _, hole := range completion.Holes {
+			if hole.X == 0 && hole.Y == 1 {
+				assert.True(t, hole.Impossible)
+				assert.Equal(t, 0, hole.Fits)
+				assert.True(t, completion.Impossible)
+				assert.Equal(t, 0., completion.Probability)
+				impossible++
+			}
+		}
+		if completion.Type == Cloister {
+			assert.Equal(t, 3, completion.Points)
+			assert.Len(t, completion.Holes, 6)
+		}
+	}
+	// the cities above and below, the roads to the left and right, and the cloister next to the hole
+	assert.Equal(t, 5, impossible)
+}
+
+// deadSpaceState creates a state with a dead space at 0,1 that has cities above and below and roads to the left and right
+func deadSpaceState(t *testing.T) *state {
+	s := newState([]string{TeamA, TeamB}, 1, nil)
+	placements := []struct {
+		tile *tile
+		x, y int
+	}{
+		{newTile(Farm, Road, Farm, Road, NilStructure, false, false), -1, 0},
+		{newTile(Farm, Road, Farm, Road, NilStructure, false, false), -1, 1},
+		{newTile(Farm, Farm, Farm, Farm, Cloister, false, false), -1, 2},
+		{newTile(Farm, Farm, City, Farm, NilStructure, false, false), 0, 2},
+		{newTile(Farm, Road, Farm, Road, NilStructure, false, false), 1, 0},
+		{newTile(Farm, Road, Farm, Road, NilStructure, false, false), 1, 1},
+	}
+	for _, placement := range placements {
+		if err := s.board.Place(placement.tile, placement.x, placement.y); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		// the placed tiles come out of the deck
+		for i, d := range s.deck.tiles {
+			if d.equals(placement.tile) {
+				s.deck.tiles = append(s.deck.tiles[:i], s.deck.tiles[i+1:]...)
+				break
+			}
+		}
+	}
+	return s
+}
+
+func Test_DeadSpaces(t *testing.T) {
+	s := deadSpaceState(t)
+	assert.Equal(t, []*DeadSpace{{X: 0, Y: 1}}, s.board.deadSpaces())
+	for _, emptySpace := range s.board.getEmptySpaces() {
+		assert.False(t, emptySpace.X == 0 && emptySpace.Y == 1)
+	}
+	clone, _ := s.board.clone()
+	assert.Equal(t, s.board.deadSpaces(), clone.deadSpaces())
+	assert.True(t, clone.isDead(0, 1))
+	assert.False(t, clone.isDead(1, 1))
+
+	// dead spaces are found again when loading a position
+	carcassonne := &Carcassonne{state: s}
+	decoded, err := decodePosition([]string{TeamA, TeamB}, 1, carcassonne.Position())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, s.board.deadSpaces(), decoded.board.deadSpaces())
+	assert.True(t, decoded.board.isDead(0, 1))
+	snapshot, _ := (&Carcassonne{state: decoded}).GetSnapshot()
+	assert.Equal(t, s.board.deadSpaces(), snapshot.MoreData.(CarcassonneSnapshotData).DeadSpaces)
+}
@@ -3,13 +3,19 @@ package go_carcassonne
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	bg "github.com/quibbble/go-boardgame"
 	"github.com/quibbble/go-boardgame/pkg/bgerr"
 )
 
+// bgnVersion is the version of notation written by GetBGN
+// version 1 has no version tag and writes every edge of a placed tile
+// version 2 records rotations and writes a placed tile as its tile type and rotation
+const bgnVersion = 2
+
 var (
-	actionToNotation = map[string]string{ActionPlaceTile: "i", ActionPlaceToken: "o", ActionRotateTileRight: "r", ActionRotateTileLeft: "l", bg.ActionSetWinners: "w"}
+	actionToNotation = map[string]string{ActionPlaceTile: "i", ActionPlaceToken: "o", ActionRotateTileRight: "r", ActionRotateTileLeft: "l", ActionResign: "q", bg.ActionSetWinners: "w"}
 	notationToAction = reverseMap(actionToNotation)
 
 	sideToNotation = map[string]string{SideTop: "t", SideRight: "r", SideBottom: "b", SideLeft: "l"}
@@ -28,18 +34,54 @@ var (
 	notationToBool = map[string]bool{"t": true, "f": false}
 )
 
+// encodeBGN writes the location then the tile followed by the hand slot when it is not the play tile
 func (p *PlaceTileActionDetails) encodeBGN() []string {
-	return []string{
-		strconv.Itoa(p.X), strconv.Itoa(p.Y),
-		structureToNotation[p.Tile.Top], structureToNotation[p.Tile.Right], structureToNotation[p.Tile.Bottom], structureToNotation[p.Tile.Left],
-		structureToNotation[p.Tile.Center],
-		boolToNotation[p.Tile.ConnectedCitySides], boolToNotation[p.Tile.Banner],
-	}
+	var notation []string
+	if typ, rotation, ok := tileType(p.Tile); ok {
+		notation = []string{strconv.Itoa(p.X), strconv.Itoa(p.Y), strconv.Itoa(typ), strconv.Itoa(rotation)}
+	} else {
+		notation = []string{
+			strconv.Itoa(p.X), strconv.Itoa(p.Y),
+			structureToNotation[p.Tile.Top], structureToNotation[p.Tile.Right], structureToNotation[p.Tile.Bottom], structureToNotation[p.Tile.Left],
+			structureToNotation[p.Tile.Center],
+			boolToNotation[p.Tile.ConnectedCitySides], boolToNotation[p.Tile.Banner],
+		}
+	}
+	if p.Slot > 0 {
+		notation = append(notation, strconv.Itoa(p.Slot))
+	}
+	return notation
+}
+
+// tileType gets the index in tiles of the tile and the number of times it is rotated right from that tile
+func tileType(details TileActionDetails) (int, int, bool) {
+	for typ, tileAmount := range tiles {
+		t := tileAmount.tile.copy()
+		for rotation := 0; rotation < 4; rotation++ {
+			if tileToActionDetails(t) == details {
+				return typ, rotation, true
+			}
+			t.RotateRight()
+		}
+	}
+	return 0, 0, false
 }
 
 func decodePlaceTileActionDetailsBGN(notation []string) (*PlaceTileActionDetails, error) {
-	if len(notation) != 9 {
-		return nil, loadFailure(fmt.Errorf("got %d but wanted %d fields in when decoding %s details", len(notation), 2, ActionPlaceTile))
+	if len(notation) == 5 || len(notation) == 10 {
+		slot, err := strconv.Atoi(notation[len(notation)-1])
+		if err != nil || slot <= 0 {
+			return nil, loadFailure(fmt.Errorf("got %s but wanted a positive hand slot", notation[len(notation)-1]))
+		}
+		details, err := decodePlaceTileActionDetailsBGN(notation[:len(notation)-1])
+		if err != nil {
+			return nil, err
+		}
+		details.Slot = slot
+		return details, nil
+	}
+	if len(notation) != 4 && len(notation) != 9 {
+		return nil, loadFailure(fmt.Errorf("got %d but wanted %d or %d fields in when decoding %s details", len(notation), 4, 9, ActionPlaceTile))
 	}
 	x, err := strconv.Atoi(notation[0])
 	if err != nil {
@@ -49,6 +91,21 @@ func decodePlaceTileActionDetailsBGN(notation []string) (*PlaceTileActionDetails
 	if err != nil {
 		return nil, loadFailure(err)
 	}
+	if len(notation) == 4 {
+		typ, err := strconv.Atoi(notation[2])
+		if err != nil || typ < 0 || typ >= len(tiles) {
+			return nil, loadFailure(fmt.Errorf("got %s but wanted a tile type from 0 to %d", notation[2], len(tiles)-1))
+		}
+		rotation, err := strconv.Atoi(notation[3])
+		if err != nil || rotation < 0 || rotation > 3 {
+			return nil, loadFailure(fmt.Errorf("got %s but wanted a rotation from 0 to 3", notation[3]))
+		}
+		t := tiles[typ].tile.copy()
+		for i := 0; i < rotation; i++ {
+			t.RotateRight()
+		}
+		return &PlaceTileActionDetails{X: x, Y: y, Tile: tileToActionDetails(t)}, nil
+	}
 	top, ok := notationToStructure[notation[2]]
 	if !ok {
 		return nil, loadFailure(fmt.Errorf("failed to get top of tile"))
@@ -127,6 +184,30 @@ func decodePlaceTokenActionDetailsBGN(notation []string) (*PlaceTokenActionDetai
 	return &PlaceTokenActionDetails{Pass: pass, X: x, Y: y, Type: token, Side: side}, nil
 }
 
+// encodeDeckOrder writes the tile types drawn first as the Deck tag e.g. 21.3.0
+func encodeDeckOrder(order []int) string {
+	notation := make([]string, 0, len(order))
+	for _, typ := range order {
+		notation = append(notation, strconv.Itoa(typ))
+	}
+	return strings.Join(notation, ".")
+}
+
+func decodeDeckOrder(notation string) ([]int, error) {
+	order := make([]int, 0)
+	for _, field := range strings.Split(notation, ".") {
+		typ, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, loadFailure(err)
+		}
+		order = append(order, typ)
+	}
+	if err := validateDeckOrder(order); err != nil {
+		return nil, loadFailure(err)
+	}
+	return order, nil
+}
+
 func loadFailure(err error) error {
 	return &bgerr.Error{
 		Err:    err,
@@ -13,8 +13,16 @@ type connection struct {
 // board - +X right +Y up
 type board struct {
 	board          []*tile // list of all tiles in order of added
+	start          *tile   // the start tile which is not one of tiles, nil if no tile on the board is
 	completeCities []*structure
 	completeRoads  []*structure
+	dead           [][2]int        // empty spaces no tile in tiles can fill in the order they are found
+	isDeadSpace    map[[2]int]bool // the empty spaces in dead for constant time lookups
+}
+
+// DeadSpace is an empty space on the board that no tile can ever fill
+type DeadSpace struct {
+	X, Y int
 }
 
 func newBoard() *board {
@@ -22,40 +30,52 @@ func newBoard() *board {
 	start.X, start.Y = 0, 0
 	return &board{
 		board: []*tile{start},
+		start: start,
 	}
 }
 
+// placedTiles gets the tiles on the board other than the start tile i.e. the tiles drawn from tiles
+func (b *board) placedTiles() []*tile {
+	placed := make([]*tile, 0, len(b.board))
+	for _, t := range b.board {
+		if t != b.start {
+			placed = append(placed, t)
+		}
+	}
+	return placed
+}
+
 func (b *board) Place(t *tile, x, y int) error {
 	if b.tile(x, y) != nil {
-		return fmt.Errorf("tile aready at %d,%d", x, y)
+		return &OccupiedError{X: x, Y: y}
 	}
 	// get all adj tiles and check if valid placement
 	sides := make(map[string]*tile)
 	for _, boardTile := range b.board {
 		if boardTile.X == x+1 && boardTile.Y == y {
 			if boardTile.Sides[SideLeft] != t.Sides[SideRight] {
-				return fmt.Errorf("invalid tile placement")
+				return edgeMismatch(t, x, y, SideRight, boardTile)
 			}
 			sides[SideRight] = boardTile
 		} else if boardTile.X == x && boardTile.Y == y+1 {
 			if boardTile.Sides[SideBottom] != t.Sides[SideTop] {
-				return fmt.Errorf("invalid tile placement")
+				return edgeMismatch(t, x, y, SideTop, boardTile)
 			}
 			sides[SideTop] = boardTile
 		} else if boardTile.X == x-1 && boardTile.Y == y {
 			if boardTile.Sides[SideRight] != t.Sides[SideLeft] {
-				return fmt.Errorf("invalid tile placement")
+				return edgeMismatch(t, x, y, SideLeft, boardTile)
 			}
 			sides[SideLeft] = boardTile
 		} else if boardTile.X == x && boardTile.Y == y-1 {
 			if boardTile.Sides[SideTop] != t.Sides[SideBottom] {
-				return fmt.Errorf("invalid tile placement")
+				return edgeMismatch(t, x, y, SideBottom, boardTile)
 			}
 			sides[SideBottom] = boardTile
 		}
 	}
 	if len(sides) <= 0 {
-		return fmt.Errorf("cannot add a disconnected tile to the board")
+		return &DisconnectedError{X: x, Y: y}
 	}
 	// update adjacent pointer values
 	if sides[SideTop] != nil {
@@ -78,9 +98,31 @@ func (b *board) Place(t *tile, x, y int) error {
 	t.X, t.Y = x, y
 	// add tile to list of tiles
 	b.board = append(b.board, t)
+	// spaces only gain neighbors so once dead they stay dead
+	for _, side := range Sides {
+		if t.adjacent[side] == nil {
+			nx, ny := neighbor(x, y, side)
+			if !b.isDead(nx, ny) && deadSpace(b.emptySpace(nx, ny)) {
+				b.addDead(nx, ny)
+			}
+		}
+	}
 	return nil
 }
 
+// edgeMismatch creates the error for t placed at x,y with side not matching neighbor
+func edgeMismatch(t *tile, x, y int, side string, neighbor *tile) *EdgeMismatchError {
+	return &EdgeMismatchError{
+		X:                 x,
+		Y:                 y,
+		Side:              side,
+		Structure:         t.Sides[side],
+		NeighborX:         neighbor.X,
+		NeighborY:         neighbor.Y,
+		NeighborStructure: neighbor.Sides[AcrossSide[side]],
+	}
+}
+
 // get a tile at x,y or return nil
 func (b *board) tile(x, y int) *tile {
 	for _, tile := range b.board {
@@ -93,24 +135,24 @@ func (b *board) tile(x, y int) *tile {
 
 // given a tile location and side that contains a city section, get the current city structure
 func (b *board) generateCity(x, y int, side string) (*structure, error) {
-	tile := b.tile(x, y)
-	if tile == nil {
+	start := b.tile(x, y)
+	if start == nil {
 		return nil, fmt.Errorf("tile does not exist at %d,%d", x, y)
 	}
-	if tile.Sides[side] != City {
+	if start.Sides[side] != City {
 		return nil, fmt.Errorf("side %s does not contain city section at tile %d,%d", side, x, y)
 	}
 	// perform DFS - don't use BFS - will lead to city side edge case
 	complete := true
-	seen := make([]*node, 0)         // keeps track of tile and sides in city
-	visited := make(map[string]bool) // key is "XY" of tile
+	seen := make([]*node, 0)        // keeps track of tile and sides in city
+	visited := make(map[*tile]bool) // tiles already added to the stack
 	stack := make([]*connection, 0)
 
 	stack = append(stack, &connection{
-		tile: tile,
+		tile: start,
 		side: side,
 	})
-	visited[fmt.Sprintf("%d%d", x, y)] = true
+	visited[start] = true
 	for len(stack) > 0 {
 		front := stack[0]
 		stack = stack[1:]
@@ -129,7 +171,7 @@ func (b *board) generateCity(x, y int, side string) (*structure, error) {
 			adjacentTile := front.tile.adjacent[s]
 			if adjacentTile == nil {
 				complete = false // city not yet completed
-			} else if visited[fmt.Sprintf("%d%d", adjacentTile.X, adjacentTile.Y)] {
+			} else if visited[adjacentTile] {
 				// edge case for disconnected city sides that end up being part of the same city
 				for _, n := range seen {
 					if adjacentTile.X == n.tile.X && adjacentTile.Y == n.tile.Y {
@@ -138,12 +180,12 @@ func (b *board) generateCity(x, y int, side string) (*structure, error) {
 						}
 					}
 				}
-			} else if !visited[fmt.Sprintf("%d%d", adjacentTile.X, adjacentTile.Y)] {
+			} else if !visited[adjacentTile] {
 				stack = append([]*connection{{
 					tile: adjacentTile,
 					side: AcrossSide[s],
 				}}, stack...)
-				visited[fmt.Sprintf("%d%d", adjacentTile.X, adjacentTile.Y)] = true
+				visited[adjacentTile] = true
 			}
 		}
 		// continued edge case for disconnected city sides that end up being part of the same city
@@ -181,24 +223,24 @@ func (b *board) generateCity(x, y int, side string) (*structure, error) {
 
 // given a tile location and side that contains a road section, get the current road structure
 func (b *board) generateRoad(x, y int, side string) (*structure, error) {
-	tile := b.tile(x, y)
-	if tile == nil {
+	start := b.tile(x, y)
+	if start == nil {
 		return nil, fmt.Errorf("tile does not exist at %d,%d", x, y)
 	}
-	if tile.Sides[side] != Road {
+	if start.Sides[side] != Road {
 		return nil, fmt.Errorf("side %s does not contain road section at tile %d,%d", side, x, y)
 	}
 	// perform BFS
 	complete := true
-	seen := make([]*node, 0)         // keeps track of tile and sides in road
-	visited := make(map[string]bool) // key is "XY" of tile
+	seen := make([]*node, 0)        // keeps track of tile and sides in road
+	visited := make(map[*tile]bool) // tiles already added to the queue
 	queue := make([]*connection, 0)
 
 	queue = append(queue, &connection{
-		tile: tile,
+		tile: start,
 		side: side,
 	})
-	visited[fmt.Sprintf("%d%d", x, y)] = true
+	visited[start] = true
 	for len(queue) > 0 {
 		front := queue[0]
 		queue = queue[1:]
@@ -217,7 +259,7 @@ func (b *board) generateRoad(x, y int, side string) (*structure, error) {
 			adjacentTile := front.tile.adjacent[s]
 			if adjacentTile == nil {
 				complete = false // road not yet completed
-			} else if visited[fmt.Sprintf("%d%d", adjacentTile.X, adjacentTile.Y)] {
+			} else if visited[adjacentTile] {
 				// edge case for disconnected road sides that end up being part of the same road
 				for _, n := range seen {
 					if adjacentTile.X == n.tile.X && adjacentTile.Y == n.tile.Y {
@@ -226,12 +268,12 @@ func (b *board) generateRoad(x, y int, side string) (*structure, error) {
 						}
 					}
 				}
-			} else if !visited[fmt.Sprintf("%d%d", adjacentTile.X, adjacentTile.Y)] {
+			} else if !visited[adjacentTile] {
 				queue = append(queue, &connection{
 					tile: adjacentTile,
 					side: AcrossSide[s],
 				})
-				visited[fmt.Sprintf("%d%d", adjacentTile.X, adjacentTile.Y)] = true
+				visited[adjacentTile] = true
 			}
 		}
 		// continued edge case for disconnected road sides that end up being part of the same road
@@ -269,24 +311,25 @@ func (b *board) generateRoad(x, y int, side string) (*structure, error) {
 
 // given a tile location and farmSide that contains farmland i.e. farm or road section, get the current farm structure
 func (b *board) generateFarm(x, y int, farmSide string) (*structure, error) {
-	tile := b.tile(x, y)
-	if tile == nil {
+	start := b.tile(x, y)
+	if start == nil {
 		return nil, fmt.Errorf("tile does not exist at %d,%d", x, y)
 	}
 	side := farmSideToSide(farmSide)
-	if tile.Sides[side] != Farm && tile.Sides[side] != Road {
+	if start.Sides[side] != Farm && start.Sides[side] != Road {
 		return nil, fmt.Errorf("side %s does not contain farmland at tile %d,%d", side, x, y)
 	}
 	// start BFS
-	seen := make([]*node, 0)         // keeps track of tile and farm sides in farm
-	visited := make(map[string]bool) // key is "XY" of tile
+	seen := make([]*node, 0)             // keeps track of tile and farm sides in farm
+	nodes := make(map[*tile]*node)       // tiles already in seen
+	visited := make(map[connection]bool) // tile and entering farm side already added to the queue
 	queue := make([]*connection, 0)
 
 	queue = append(queue, &connection{
-		tile: tile,
+		tile: start,
 		side: farmSide,
 	})
-	visited[fmt.Sprintf("%d%d%s", x, y, farmSide)] = true
+	visited[connection{tile: start, side: farmSide}] = true
 
 	for len(queue) > 0 {
 		front := queue[0]
@@ -298,35 +341,31 @@ func (b *board) generateFarm(x, y int, farmSide string) (*structure, error) {
 		}
 		sides = append(sides, front.side)
 		// if tile not already in seen add it
-		found := false
-		for _, s := range seen {
-			if fmt.Sprintf("%d%d", s.tile.X, s.tile.Y) == fmt.Sprintf("%d%d", front.tile.X, front.tile.Y) {
-				// check if all sides have been found and if not add those sides
-				for _, side := range sides {
-					if !contains(s.sides, side) {
-						s.sides = append(s.sides, side)
-					}
+		if n, ok := nodes[front.tile]; ok {
+			// check if all sides have been found and if not add those sides
+			for _, side := range sides {
+				if !contains(n.sides, side) {
+					n.sides = append(n.sides, side)
 				}
-				found = true
-				break
 			}
-		}
-		if !found {
-			seen = append(seen, &node{
+		} else {
+			n := &node{
 				tile:  front.tile,
 				sides: sides,
-			})
+			}
+			seen = append(seen, n)
+			nodes[front.tile] = n
 		}
 		// perform BFS
 		for _, farmSide := range sides {
 			side := farmSideToSide(farmSide)
 			adjacentTile := front.tile.adjacent[side]
-			if adjacentTile != nil && !visited[fmt.Sprintf("%d%d%s", adjacentTile.X, adjacentTile.Y, farmSide)] {
+			if adjacentTile != nil && !visited[connection{tile: adjacentTile, side: farmSide}] {
 				queue = append(queue, &connection{
 					tile: adjacentTile,
 					side: AcrossFarmSide[farmSide],
 				})
-				visited[fmt.Sprintf("%d%d%s", adjacentTile.X, adjacentTile.Y, farmSide)] = true
+				visited[connection{tile: adjacentTile, side: farmSide}] = true
 			}
 		}
 	}
@@ -358,13 +397,7 @@ func (b *board) playable(t *tile) bool {
 	copied := t.copy()
 	for i := 0; i < 4; i++ {
 		for _, emptySpace := range emptySpaces {
-			valid := true
-			for _, side := range Sides {
-				if emptySpace.adjacent[side] != nil && emptySpace.adjacent[side].Sides[AcrossSide[side]] != copied.Sides[side] {
-					valid = false
-				}
-			}
-			if valid {
+			if fits(emptySpace, copied) {
 				return true
 			}
 		}
@@ -373,10 +406,96 @@ func (b *board) playable(t *tile) bool {
 	return false
 }
 
-// gets a list of empty spaces that are potential place locations
+// fits checks whether the tile in its current rotation matches all tiles adjacent to the empty space
+func fits(emptySpace, t *tile) bool {
+	for _, side := range Sides {
+		if emptySpace.adjacent[side] != nil && emptySpace.adjacent[side].Sides[AcrossSide[side]] != t.Sides[side] {
+			return false
+		}
+	}
+	return true
+}
+
+// neighbor gets the location next to x,y on side
+func neighbor(x, y int, side string) (int, int) {
+	switch side {
+	case SideTop:
+		return x, y + 1
+	case SideRight:
+		return x + 1, y
+	case SideBottom:
+		return x, y - 1
+	case SideLeft:
+		return x - 1, y
+	}
+	return x, y
+}
+
+// emptySpace gets the empty space at x,y with the tiles adjacent to it
+func (b *board) emptySpace(x, y int) *tile {
+	emptySpace := emptySpaceTile(x, y)
+	for _, side := range Sides {
+		if adjacent := b.tile(neighbor(x, y, side)); adjacent != nil {
+			emptySpace.adjacent[side] = adjacent
+		}
+	}
+	return emptySpace
+}
+
+// rotations are the tiles in tiles in each of their four rotations so spaces are checked without rotating tiles
+var rotations = func() [][]*tile {
+	rotations := make([][]*tile, len(tiles))
+	for i, tileAmount := range tiles {
+		rotated := tileAmount.tile.copy()
+		for r := 0; r < 4; r++ {
+			rotations[i] = append(rotations[i], rotated.copy())
+			rotated.RotateRight()
+		}
+	}
+	return rotations
+}()
+
+// deadSpace checks whether no tile in tiles fits emptySpace in any rotation
+func deadSpace(emptySpace *tile) bool {
+	for _, rotated := range rotations {
+		for _, t := range rotated {
+			if fits(emptySpace, t) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isDead checks whether the empty space at x,y can never be filled
+func (b *board) isDead(x, y int) bool {
+	return b.isDeadSpace[[2]int{x, y}]
+}
+
+// addDead marks the empty space at x,y as one that can never be filled
+func (b *board) addDead(x, y int) {
+	if b.isDeadSpace == nil {
+		b.isDeadSpace = make(map[[2]int]bool)
+	}
+	b.isDeadSpace[[2]int{x, y}] = true
+	b.dead = append(b.dead, [2]int{x, y})
+}
+
+// deadSpaces gets the empty spaces that can never be filled in the order they are found
+func (b *board) deadSpaces() []*DeadSpace {
+	dead := make([]*DeadSpace, 0, len(b.dead))
+	for _, d := range b.dead {
+		dead = append(dead, &DeadSpace{X: d[0], Y: d[1]})
+	}
+	return dead
+}
+
+// gets a list of empty spaces that are potential place locations in the order they are found
+// dead spaces are left out as no tile can be placed there
 func (b *board) getEmptySpaces() []*tile {
 	// go through board and get all empty spaces
-	emptySpaces := make(map[string]*tile)
+	emptySpaces := make(map[[2]int]*tile)
+	result := make([]*tile, 0)
 	for _, boardTile := range b.board {
 		for _, side := range Sides {
 			x := boardTile.X
@@ -390,8 +509,10 @@ func (b *board) getEmptySpaces() []*tile {
 			} else if side == SideLeft {
 				x--
 			}
-			if boardTile.adjacent[side] == nil && emptySpaces[fmt.Sprintf("%d%d", x, y)] == nil {
-				emptySpaces[fmt.Sprintf("%d%d", x, y)] = emptySpaceTile(x, y)
+			if boardTile.adjacent[side] == nil && emptySpaces[[2]int{x, y}] == nil && !b.isDead(x, y) {
+				emptySpace := emptySpaceTile(x, y)
+				emptySpaces[[2]int{x, y}] = emptySpace
+				result = append(result, emptySpace)
 			}
 		}
 	}
@@ -409,14 +530,47 @@ func (b *board) getEmptySpaces() []*tile {
 			} else if side == SideLeft {
 				x--
 			}
-			if boardTile.adjacent[side] == nil {
-				emptySpaces[fmt.Sprintf("%d%d", x, y)].adjacent[AcrossSide[side]] = boardTile
+			if emptySpace := emptySpaces[[2]int{x, y}]; boardTile.adjacent[side] == nil && emptySpace != nil {
+				emptySpace.adjacent[AcrossSide[side]] = boardTile
 			}
 		}
 	}
-	result := make([]*tile, 0)
-	for _, emptySpace := range emptySpaces {
-		result = append(result, emptySpace)
-	}
 	return result
 }
+
+// clone creates a deep copy of the board relinking all adjacent tiles and complete structures to the copied tiles
+func (b *board) clone() (*board, map[*tile]*tile) {
+	copies := make([]tile, len(b.board))
+	mapping := make(map[*tile]*tile, len(b.board))
+	tiles := make([]*tile, len(b.board))
+	for i, t := range b.board {
+		t.cloneInto(&copies[i])
+		tiles[i] = &copies[i]
+		mapping[t] = tiles[i]
+	}
+	for _, t := range b.board {
+		for side, adjacent := range t.adjacent {
+			mapping[t].adjacent[side] = mapping[adjacent]
+		}
+	}
+	completeCities := make([]*structure, len(b.completeCities))
+	for i, city := range b.completeCities {
+		completeCities[i] = city.clone(mapping)
+	}
+	completeRoads := make([]*structure, len(b.completeRoads))
+	for i, road := range b.completeRoads {
+		completeRoads[i] = road.clone(mapping)
+	}
+	isDeadSpace := make(map[[2]int]bool, len(b.isDeadSpace))
+	for space := range b.isDeadSpace {
+		isDeadSpace[space] = true
+	}
+	return &board{
+		board:          tiles,
+		start:          mapping[b.start],
+		completeCities: completeCities,
+		completeRoads:  completeRoads,
+		dead:           append([][2]int(nil), b.dead...),
+		isDeadSpace:    isDeadSpace,
+	}, mapping
+}
@@ -0,0 +1,197 @@
+package go_carcassonne
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+
+	bg "github.com/quibbble/go-boardgame"
+)
+
+// tokenWeight is the value given to each token a team has yet to place when evaluating a game
+const tokenWeight = 0.5
+
+// Bot is a computer player that chooses actions for a team
+type Bot interface {
+	// Action gets the next action team should perform in game
+	// Bots should only rely on what team can see i.e. not the order of the deck or the play tiles of other teams
+	Action(game *Carcassonne, team string) (*bg.BoardGameAction, error)
+}
+
+// BotFactory creates a new bot using seed to generate deterministic randomness
+type BotFactory func(seed int64) Bot
+
+var (
+	botsMu sync.RWMutex
+	bots   = map[string]BotFactory{
+		"random": func(seed int64) Bot { return NewRandomBot(seed) },
+		"greedy": func(seed int64) Bot { return NewGreedyBot(seed) },
+		"mcts":   func(seed int64) Bot { return NewMCTSBot(&MCTSBotOptions{Seed: seed}) },
+	}
+)
+
+// RegisterBot makes a bot available by name replacing any bot already registered with that name
+func RegisterBot(name string, factory BotFactory) {
+	botsMu.Lock()
+	defer botsMu.Unlock()
+	bots[name] = factory
+}
+
+// NewBot creates a new registered bot by name
+func NewBot(name string, seed int64) (Bot, error) {
+	botsMu.RLock()
+	defer botsMu.RUnlock()
+	factory, ok := bots[name]
+	if !ok {
+		return nil, fmt.Errorf("bot %s not registered", name)
+	}
+	return factory(seed), nil
+}
+
+// BotNames gets the names of all registered bots in sorted order
+func BotNames() []string {
+	botsMu.RLock()
+	defer botsMu.RUnlock()
+	names := make([]string, 0, len(bots))
+	for name := range bots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RandomBot places its tile and token uniformly at random from all valid actions
+type RandomBot struct {
+	random *rand.Rand
+}
+
+func NewRandomBot(seed int64) *RandomBot {
+	return &RandomBot{
+		random: rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (b *RandomBot) Action(game *Carcassonne, team string) (*bg.BoardGameAction, error) {
+	actions, err := game.state.moves(team)
+	if err != nil {
+		return nil, err
+	}
+	return actions[b.random.Intn(len(actions))], nil
+}
+
+// GreedyBot chooses the tile and token placement that maximizes its immediate score plus the value of its tokens on the board
+type GreedyBot struct {
+	random *rand.Rand
+}
+
+func NewGreedyBot(seed int64) *GreedyBot {
+	return &GreedyBot{
+		random: rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (b *GreedyBot) Action(game *Carcassonne, team string) (*bg.BoardGameAction, error) {
+	// simulate on a game where the tiles team cannot see are shuffled so the real next tile is never drawn
+	game = determinize(game, team, game.state.unseenTiles(team), b.random)
+	actions, err := game.state.moves(team)
+	if err != nil {
+		return nil, err
+	}
+	best := make([]*bg.BoardGameAction, 0)
+	bestValue := 0.
+	for _, action := range actions {
+		clone := game.searchClone()
+		if err := clone.Do(action); err != nil {
+			return nil, err
+		}
+		// look ahead to the best token placement after placing the tile
+		value, err := bestTokenValue(clone, team)
+		if err != nil {
+			return nil, err
+		}
+		if len(best) == 0 || value > bestValue {
+			best = []*bg.BoardGameAction{action}
+			bestValue = value
+		} else if value == bestValue {
+			best = append(best, action)
+		}
+	}
+	return best[b.random.Intn(len(best))], nil
+}
+
+// determinize creates a copy of game for simulating moves in which the tiles unseen by team are randomly redistributed
+// between the deck below any revealed tiles and the hands of the other teams unless hands are public
+func determinize(game *Carcassonne, team string, unseen []*tile, random *rand.Rand) *Carcassonne {
+	game = game.searchClone()
+	unseen = append(make([]*tile, 0, len(unseen)), unseen...)
+	random.Shuffle(len(unseen), func(i, j int) {
+		unseen[i], unseen[j] = unseen[j], unseen[i]
+	})
+	revealed := game.state.revealed()
+	for _, other := range game.state.teams {
+		if other == team || game.state.publicHands {
+			continue
+		}
+		if game.state.playTiles[other] != nil && len(unseen) > 0 {
+			game.state.playTiles[other] = unseen[0].copy()
+			unseen = unseen[1:]
+		}
+		for i := range game.state.hands[other] {
+			if len(unseen) > 0 {
+				game.state.hands[other][i] = unseen[0].copy()
+				unseen = unseen[1:]
+			}
+		}
+	}
+	// revealed tiles stay on top of the deck
+	for i := len(revealed) - 1; i >= 0; i-- {
+		unseen = append(unseen, revealed[i])
+	}
+	game.state.deck.tiles = unseen
+	game.state.deck.ordered = len(revealed)
+	return game
+}
+
+// bestTokenValue gets the value of game for team after team makes its best token placement if it still has to place a token
+func bestTokenValue(game *Carcassonne, team string) (float64, error) {
+	if len(game.state.winners) > 0 || game.state.turn != team || game.state.playTiles[team] != nil {
+		values, err := evaluate(game.state)
+		if err != nil {
+			return 0, err
+		}
+		return values[team], nil
+	}
+	actions, err := game.state.moves(team)
+	if err != nil {
+		return 0, err
+	}
+	best := 0.
+	for i, action := range actions {
+		clone := game.searchClone()
+		if err := clone.Do(action); err != nil {
+			return 0, err
+		}
+		values, err := evaluate(clone.state)
+		if err != nil {
+			return 0, err
+		}
+		if i == 0 || values[team] > best {
+			best = values[team]
+		}
+	}
+	return best, nil
+}
+
+// evaluate gets the score each team would have if the game ended now plus a value for each token the team has yet to place
+func evaluate(s *state) (map[string]float64, error) {
+	scores, err := s.projectedScores()
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]float64, len(scores))
+	for team, score := range scores {
+		values[team] = float64(score) + tokenWeight*float64(s.tokens[team])
+	}
+	return values, nil
+}
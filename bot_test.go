@@ -0,0 +1,85 @@
+package go_carcassonne
+
+import (
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Bots(t *testing.T) {
+	greedyWins := 0
+	games := 4
+	for seed := int64(0); seed < int64(games); seed++ {
+		// rotate seats so both bots play first
+		teams := []string{TeamA, TeamB}
+		bots := map[string]Bot{TeamA: NewGreedyBot(seed), TeamB: NewRandomBot(seed)}
+		if seed%2 == 1 {
+			bots = map[string]Bot{TeamA: NewRandomBot(seed), TeamB: NewGreedyBot(seed)}
+		}
+		carcassonne := playBots(t, teams, bots, seed)
+		if (seed%2 == 0 && carcassonne.state.scores[TeamA] > carcassonne.state.scores[TeamB]) ||
+			(seed%2 == 1 && carcassonne.state.scores[TeamB] > carcassonne.state.scores[TeamA]) {
+			greedyWins++
+		}
+	}
+	assert.Equal(t, games, greedyWins, "greedy bot should beat random bot")
+}
+
+func Test_GreedyBot_HiddenTiles(t *testing.T) {
+	// the order of the deck and the tiles of other teams cannot change what the greedy bot plays
+	for _, actions := range []int{0, 11, 30, 57} {
+		carcassonne := playRandom(t, []string{TeamA, TeamB}, 5, actions)
+		team := carcassonne.state.turn
+		other := TeamA
+		if team == TeamA {
+			other = TeamB
+		}
+		hidden := carcassonne.Clone()
+		tiles := hidden.state.deck.tiles
+		for i, j := 0, len(tiles)-1; i < j; i, j = i+1, j-1 {
+			tiles[i], tiles[j] = tiles[j], tiles[i]
+		}
+		if len(tiles) > 0 && hidden.state.playTiles[other] != nil {
+			hidden.state.playTiles[other], tiles[0] = tiles[0], hidden.state.playTiles[other]
+		}
+		expected, err := NewGreedyBot(1).Action(carcassonne, team)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		got, err := NewGreedyBot(1).Action(hidden, team)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		assert.Equal(t, expected, got, actions)
+	}
+}
+
+// playBots plays a game to completion using bots for every team
+func playBots(t testing.TB, teams []string, bots map[string]Bot, seed int64) *Carcassonne {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams: teams,
+		MoreOptions: CarcassonneMoreOptions{
+			Seed: seed,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for len(carcassonne.state.winners) == 0 {
+		team := carcassonne.state.turn
+		action, err := bots[team].Action(carcassonne, team)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if err := carcassonne.Do(action); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	return carcassonne
+}
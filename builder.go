@@ -25,6 +25,15 @@ func (b *Builder) Load(game *bgn.Game) (bg.BoardGameWithBGN, error) {
 	if game.Tags["Game"] != key {
 		return nil, loadFailure(fmt.Errorf("game tag does not match game key"))
 	}
+	if versionStr, ok := game.Tags["Version"]; ok {
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, loadFailure(err)
+		}
+		if version > bgnVersion {
+			return nil, loadFailure(fmt.Errorf("version %d is newer than the supported version %d", version, bgnVersion))
+		}
+	}
 	teamsStr, ok := game.Tags["Teams"]
 	if !ok {
 		return nil, loadFailure(fmt.Errorf("missing teams tag"))
@@ -38,20 +47,73 @@ func (b *Builder) Load(game *bgn.Game) (bg.BoardGameWithBGN, error) {
 	if err != nil {
 		return nil, loadFailure(err)
 	}
+	var order []int
+	if deckStr, ok := game.Tags["Deck"]; ok && deckStr != "" {
+		order, err = decodeDeckOrder(deckStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var solo *SoloOptions
+	if soloStr, ok := game.Tags["Solo"]; ok {
+		if solo, err = decodeSolo(soloStr); err != nil {
+			return nil, err
+		}
+	}
+	var partnerships [][]string
+	if partnershipsStr, ok := game.Tags["Partnerships"]; ok {
+		if partnerships, err = decodePartnerships(teams, partnershipsStr); err != nil {
+			return nil, err
+		}
+	}
+	var clock *ClockOptions
+	if clockStr, ok := game.Tags["Clock"]; ok {
+		if clock, err = decodeClock(clockStr); err != nil {
+			return nil, err
+		}
+	}
+	handSize := 0
+	if handSizeStr, ok := game.Tags["HandSize"]; ok {
+		if handSize, err = strconv.Atoi(handSizeStr); err != nil {
+			return nil, loadFailure(err)
+		}
+	}
+	reveal := 0
+	if revealStr, ok := game.Tags["Reveal"]; ok {
+		if reveal, err = strconv.Atoi(revealStr); err != nil {
+			return nil, loadFailure(err)
+		}
+	}
 	g, err := b.CreateWithBGN(&bg.BoardGameOptions{
 		Teams: teams,
 		MoreOptions: CarcassonneMoreOptions{
-			Seed: int64(seed),
+			Seed:           int64(seed),
+			Deck:           order,
+			Position:       game.Tags["Position"],
+			Goal:           game.Tags["Goal"],
+			Solo:           solo,
+			Partnerships:   partnerships,
+			SharedMajority: game.Tags["SharedMajority"] == "true",
+			Clock:          clock,
+			ResignTokens:   game.Tags["ResignTokens"],
+			HandSize:       handSize,
+			Visibility:     game.Tags["Visibility"],
+			Reveal:         reveal,
 		},
 	})
 	if err != nil {
 		return nil, err
 	}
-	for _, action := range game.Actions {
-		if action.TeamIndex >= len(teams) {
+	// actions of the automa follow the teams the game was created with
+	actionTeams := teams
+	if solo != nil && solo.Automa {
+		actionTeams = append(teams[:len(teams):len(teams)], AutomaTeam)
+	}
+	for i, action := range game.Actions {
+		if action.TeamIndex >= len(actionTeams) {
 			return nil, loadFailure(fmt.Errorf("team index %d out of range", action.TeamIndex))
 		}
-		team := teams[action.TeamIndex]
+		team := actionTeams[action.TeamIndex]
 		actionType := notationToAction[string(action.ActionKey)]
 		if actionType == "" {
 			return nil, loadFailure(fmt.Errorf("invalid action key %s", string(action.ActionKey)))
@@ -70,6 +132,8 @@ func (b *Builder) Load(game *bgn.Game) (bg.BoardGameWithBGN, error) {
 				return nil, err
 			}
 			details = result
+		case ActionResign:
+			// resigning has no details
 		case bg.ActionSetWinners:
 			result, err := bg.DecodeSetWinnersActionDetailsBGN(action.Details, teams)
 			if err != nil {
@@ -77,7 +141,7 @@ func (b *Builder) Load(game *bgn.Game) (bg.BoardGameWithBGN, error) {
 			}
 			details = result
 		}
-		if err := g.Do(&bg.BoardGameAction{
+		if err := g.(*Carcassonne).replay(i, &bg.BoardGameAction{
 			Team:        team,
 			ActionType:  actionType,
 			MoreDetails: details,
@@ -85,6 +149,12 @@ func (b *Builder) Load(game *bgn.Game) (bg.BoardGameWithBGN, error) {
 			return nil, err
 		}
 	}
+	// the banks are restored after replaying as replayed turns take no time
+	if banksStr, ok := game.Tags["ClockBanks"]; ok && g.(*Carcassonne).clock != nil {
+		if err := g.(*Carcassonne).clock.restoreBanks(teams, banksStr); err != nil {
+			return nil, err
+		}
+	}
 	return g, nil
 }
 
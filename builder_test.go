@@ -118,6 +118,48 @@ func Test_Builder_Notations(t *testing.T) {
 			},
 			shouldError: false,
 		},
+		{
+			name: "should do actions with compact tile notation and rotations",
+			bgn: &bgn.Game{
+				Tags: tags,
+				Actions: []bgn.Action{
+					{TeamIndex: 0, ActionKey: 'r'},
+					{TeamIndex: 0, ActionKey: 'l'},
+					{
+						TeamIndex: 0,
+						ActionKey: 'i',
+						Details:   []string{"1", "0", "21", "0"},
+					},
+				},
+			},
+			shouldError: false,
+		},
+		{
+			name: "unknown tile type should error",
+			bgn: &bgn.Game{
+				Tags: tags,
+				Actions: []bgn.Action{
+					{
+						TeamIndex: 0,
+						ActionKey: 'i',
+						Details:   []string{"1", "0", "99", "0"},
+					},
+				},
+			},
+			shouldError: true,
+		},
+		{
+			name: "newer version should error",
+			bgn: &bgn.Game{
+				Tags: map[string]string{
+					"Game":    key,
+					"Teams":   "TeamA, TeamB",
+					"Seed":    "123",
+					"Version": "3",
+				},
+			},
+			shouldError: true,
+		},
 	}
 
 	builder := Builder{}
@@ -126,3 +168,68 @@ func Test_Builder_Notations(t *testing.T) {
 		assert.Equal(t, test.shouldError, err != nil, test.name)
 	}
 }
+
+func Test_Builder_BGNRotations(t *testing.T) {
+	builder := Builder{}
+	carcassonne, err := builder.CreateWithBGN(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 123},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, action := range []*bg.BoardGameAction{
+		{Team: TeamA, ActionType: ActionRotateTileRight},
+		{Team: TeamA, ActionType: ActionRotateTileRight},
+		{Team: TeamA, ActionType: ActionRotateTileLeft},
+		{
+			Team:       TeamA,
+			ActionType: ActionPlaceTile,
+			MoreDetails: PlaceTileActionDetails{
+				X: 1, Y: 0, Tile: TileActionDetails{Farm, Farm, Road, Road, NilStructure, false, false},
+			},
+		},
+	} {
+		if err := carcassonne.Do(action); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+
+	game := carcassonne.GetBGN()
+	assert.Equal(t, "2", game.Tags["Version"])
+	keys := make([]rune, 0)
+	for _, action := range game.Actions {
+		keys = append(keys, action.ActionKey)
+	}
+	assert.Equal(t, []rune{'r', 'r', 'l', 'i'}, keys)
+	assert.Equal(t, []string{"1", "0", "21", "0"}, game.Actions[3].Details)
+
+	parsed, err := bgn.Parse(game.String())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	loaded, err := builder.Load(parsed)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, game.Actions, loaded.GetBGN().Actions)
+
+	// every rotation of every tile type round trips through the compact notation
+	for typ, tileAmount := range tiles {
+		tile := tileAmount.tile.copy()
+		for rotation := 0; rotation < 4; rotation++ {
+			details := &PlaceTileActionDetails{X: 2, Y: -3, Tile: tileToActionDetails(tile)}
+			decoded, err := decodePlaceTileActionDetailsBGN(details.encodeBGN())
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			assert.Equal(t, details, decoded, "type %d rotation %d", typ, rotation)
+			tile.RotateRight()
+		}
+	}
+}
@@ -2,7 +2,7 @@ package go_carcassonne
 
 import (
 	"fmt"
-	"math/rand"
+	"strconv"
 	"strings"
 
 	"github.com/mitchellh/mapstructure"
@@ -12,18 +12,42 @@ import (
 )
 
 const (
-	minTeams = 2
-	maxTeams = 5
+	minTeams    = 2
+	maxTeams    = 5
+	maxHandSize = 3
 )
 
 type Carcassonne struct {
 	state   *state
 	actions []*bg.BoardGameAction
 	options *CarcassonneMoreOptions
+	puzzle  *puzzle // goal of a single team game, nil if not a puzzle
+	solo    *solo   // nil if not a solo game
+	clock   *clock  // nil if the game is not timed
+
+	onAction func(action *bg.BoardGameAction) // called after each applied action including those of the automa, nil if unset
 }
 
 func NewCarcassonne(options *bg.BoardGameOptions) (*Carcassonne, error) {
-	if len(options.Teams) < minTeams {
+	var details CarcassonneMoreOptions
+	if err := mapstructure.Decode(options.MoreOptions, &details); err != nil {
+		return nil, &bgerr.Error{
+			Err:    err,
+			Status: bgerr.StatusInvalidOption,
+		}
+	}
+	single := details.Goal != "" || details.Solo != nil
+	if details.Goal != "" && details.Solo != nil {
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("cannot set both a goal and solo options"),
+			Status: bgerr.StatusInvalidOption,
+		}
+	} else if single && len(options.Teams) != 1 {
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("puzzles and solo games are played by exactly 1 team"),
+			Status: bgerr.StatusInvalidOption,
+		}
+	} else if !single && len(options.Teams) < minTeams {
 		return nil, &bgerr.Error{
 			Err:    fmt.Errorf("at least %d teams required to create a game of %s", minTeams, key),
 			Status: bgerr.StatusTooFewTeams,
@@ -39,22 +63,209 @@ func NewCarcassonne(options *bg.BoardGameOptions) (*Carcassonne, error) {
 			Status: bgerr.StatusInvalidOption,
 		}
 	}
-	var details CarcassonneMoreOptions
-	if err := mapstructure.Decode(options.MoreOptions, &details); err != nil {
+	if err := validateDeckOrder(details.Deck); err != nil {
 		return nil, &bgerr.Error{
 			Err:    err,
 			Status: bgerr.StatusInvalidOption,
 		}
 	}
+	teams := options.Teams
+	if details.Solo != nil && details.Solo.Automa {
+		if teams[0] == AutomaTeam {
+			return nil, &bgerr.Error{
+				Err:    fmt.Errorf("%s is played by the automa", AutomaTeam),
+				Status: bgerr.StatusInvalidOption,
+			}
+		}
+		teams = []string{teams[0], AutomaTeam}
+	}
+	state := newState(teams, details.Seed, details.Deck)
+	if details.Position != "" {
+		if len(details.Deck) > 0 {
+			return nil, &bgerr.Error{
+				Err:    fmt.Errorf("cannot set both a deck and a position as the position includes the deck"),
+				Status: bgerr.StatusInvalidOption,
+			}
+		}
+		var err error
+		if state, err = decodePosition(teams, details.Seed, details.Position); err != nil {
+			return nil, &bgerr.Error{
+				Err:    fmt.Errorf("invalid position: %w", err),
+				Status: bgerr.StatusInvalidOption,
+			}
+		}
+	}
+	if details.HandSize < 0 || details.HandSize > maxHandSize {
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("got hand size %d but wanted 1 to %d tiles", details.HandSize, maxHandSize),
+			Status: bgerr.StatusInvalidOption,
+		}
+	} else if details.HandSize > 1 {
+		state.deal(details.HandSize)
+	}
+	switch details.Visibility {
+	case "", VisibilityPrivate:
+	case VisibilityPublic:
+		state.publicHands = true
+	default:
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("got visibility %s but wanted %s or %s", details.Visibility, VisibilityPrivate, VisibilityPublic),
+			Status: bgerr.StatusInvalidOption,
+		}
+	}
+	if details.Reveal < 0 {
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("got reveal %d but wanted at least 0 tiles", details.Reveal),
+			Status: bgerr.StatusInvalidOption,
+		}
+	}
+	state.reveal = details.Reveal
+	switch details.ResignTokens {
+	case "":
+	case ResignReturn, ResignFreeze:
+		state.resignTokens = details.ResignTokens
+	default:
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("got resign tokens %s but wanted %s or %s", details.ResignTokens, ResignReturn, ResignFreeze),
+			Status: bgerr.StatusInvalidOption,
+		}
+	}
+	if len(details.Partnerships) > 0 {
+		if single {
+			return nil, &bgerr.Error{
+				Err:    fmt.Errorf("partnerships cannot be used in puzzles or solo games"),
+				Status: bgerr.StatusInvalidOption,
+			}
+		}
+		if err := validatePartnerships(options.Teams, details.Partnerships); err != nil {
+			return nil, &bgerr.Error{
+				Err:    err,
+				Status: bgerr.StatusInvalidOption,
+			}
+		}
+		state.partnerships = details.Partnerships
+		state.sharedMajority = details.SharedMajority
+	} else if details.SharedMajority {
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("shared majority requires partnerships"),
+			Status: bgerr.StatusInvalidOption,
+		}
+	}
+	var puzzle *puzzle
+	if details.Goal != "" {
+		var err error
+		if puzzle, err = newPuzzle(details.Goal, state); err != nil {
+			return nil, &bgerr.Error{
+				Err:    fmt.Errorf("invalid goal: %w", err),
+				Status: bgerr.StatusInvalidOption,
+			}
+		}
+	}
+	var solo *solo
+	if details.Solo != nil {
+		var err error
+		if solo, err = newSolo(details.Solo, details.Seed); err != nil {
+			return nil, &bgerr.Error{
+				Err:    fmt.Errorf("invalid solo options: %w", err),
+				Status: bgerr.StatusInvalidOption,
+			}
+		}
+	}
+	var clock *clock
+	if details.Clock != nil {
+		var err error
+		if clock, err = newClock(details.Clock, options.Teams); err != nil {
+			return nil, &bgerr.Error{
+				Err:    fmt.Errorf("invalid clock options: %w", err),
+				Status: bgerr.StatusInvalidOption,
+			}
+		}
+	}
 	return &Carcassonne{
-		state:   newState(options.Teams, rand.New(rand.NewSource(details.Seed))),
+		state:   state,
 		actions: make([]*bg.BoardGameAction, 0),
 		options: &details,
+		puzzle:  puzzle,
+		solo:    solo,
+		clock:   clock,
 	}, nil
 }
 
+// Clone returns a fully independent copy of the game including the board, tokens, deck order, and random state
+func (c *Carcassonne) Clone() *Carcassonne {
+	options := *c.options
+	var puzzle *puzzle
+	if c.puzzle != nil {
+		copied := *c.puzzle
+		puzzle = &copied
+	}
+	var solo *solo
+	if c.solo != nil {
+		solo = c.solo.clone()
+	}
+	var clock *clock
+	if c.clock != nil {
+		clock = c.clock.clone()
+	}
+	return &Carcassonne{
+		state:   c.state.clone(),
+		actions: append(make([]*bg.BoardGameAction, 0, len(c.actions)), c.actions...),
+		options: &options,
+		puzzle:  puzzle,
+		solo:    solo,
+		clock:   clock,
+	}
+}
+
+// searchClone copies the game for bots and solvers to simulate moves on
+// the clock is dropped so simulated moves are never timed out by the time the search itself takes
+func (c *Carcassonne) searchClone() *Carcassonne {
+	clone := c.Clone()
+	clone.clock = nil
+	return clone
+}
+
 func (c *Carcassonne) Do(action *bg.BoardGameAction) error {
-	if len(c.state.winners) > 0 {
+	if c.clock == nil {
+		return c.play(action)
+	}
+	if err := c.CheckClock(); err != nil {
+		return err
+	}
+	team := c.state.turn
+	if err := c.play(action); err != nil {
+		return err
+	}
+	if c.turnEnded(team, action) {
+		c.clock.endTurn(team)
+	}
+	return nil
+}
+
+// play does action then updates the puzzle or solo game
+func (c *Carcassonne) play(action *bg.BoardGameAction) error {
+	if err := c.do(action); err != nil {
+		return err
+	}
+	if c.puzzle != nil {
+		return c.puzzle.update(c.state, action)
+	}
+	if c.solo != nil {
+		if err := c.playAutoma(); err != nil {
+			return err
+		}
+		return c.solo.update(c.state)
+	}
+	return nil
+}
+
+// over checks whether the game has ended including puzzles and solo games that were lost
+func (c *Carcassonne) over() bool {
+	return len(c.state.winners) > 0 || (c.puzzle != nil && c.puzzle.over()) || (c.solo != nil && c.solo.lost)
+}
+
+func (c *Carcassonne) do(action *bg.BoardGameAction) error {
+	if c.over() {
 		return &bgerr.Error{
 			Err:    fmt.Errorf("game already over"),
 			Status: bgerr.StatusGameOver,
@@ -65,10 +276,12 @@ func (c *Carcassonne) Do(action *bg.BoardGameAction) error {
 		if err := c.state.RotateTileRight(action.Team); err != nil {
 			return err
 		}
+		c.actions = append(c.actions, action)
 	case ActionRotateTileLeft:
 		if err := c.state.RotateTileLeft(action.Team); err != nil {
 			return err
 		}
+		c.actions = append(c.actions, action)
 	case ActionPlaceTile:
 		var details PlaceTileActionDetails
 		if err := mapstructure.Decode(action.MoreDetails, &details); err != nil {
@@ -77,8 +290,7 @@ func (c *Carcassonne) Do(action *bg.BoardGameAction) error {
 				Status: bgerr.StatusInvalidActionDetails,
 			}
 		}
-		tile := newTile(details.Tile.Top, details.Tile.Right, details.Tile.Bottom, details.Tile.Left, details.Tile.Center, details.Tile.ConnectedCitySides, details.Tile.Banner)
-		if err := c.state.PlaceTile(action.Team, tile, details.X, details.Y); err != nil {
+		if err := c.state.PlaceTile(action.Team, details.Slot, details.Tile.tile(), details.X, details.Y); err != nil {
 			return err
 		}
 		c.actions = append(c.actions, action)
@@ -94,6 +306,11 @@ func (c *Carcassonne) Do(action *bg.BoardGameAction) error {
 			return err
 		}
 		c.actions = append(c.actions, action)
+	case ActionResign:
+		if err := c.state.Resign(action.Team); err != nil {
+			return err
+		}
+		c.actions = append(c.actions, action)
 	case bg.ActionSetWinners:
 		var details bg.SetWinnersActionDetails
 		if err := mapstructure.Decode(action.MoreDetails, &details); err != nil {
@@ -112,6 +329,9 @@ func (c *Carcassonne) Do(action *bg.BoardGameAction) error {
 			Status: bgerr.StatusUnknownActionType,
 		}
 	}
+	if c.onAction != nil {
+		c.onAction(action)
+	}
 	return nil
 }
 
@@ -128,13 +348,49 @@ func (c *Carcassonne) GetSnapshot(team ...string) (*bg.BoardGameSnapshot, error)
 		BoardTokens:     c.state.boardTokens,
 		Tokens:          c.state.tokens,
 		Scores:          c.state.scores,
+		FeatureScores:   c.state.featureScores,
 		TilesRemaining:  len(c.state.deck.tiles),
+		Resigned:        c.state.resigned,
 	}
 	if len(team) == 1 {
 		details.PlayTile = c.state.playTiles[team[0]]
+		details.Hand = c.state.hands[team[0]]
+	}
+	if len(team) == 0 || c.state.publicHands {
+		details.Hands = make(map[string][]*tile, len(c.state.teams))
+		for _, t := range c.state.teams {
+			details.Hands[t] = c.state.hand(t)
+		}
+	}
+	if c.state.reveal > 0 {
+		details.Revealed = c.state.revealed()
+	}
+	counts := c.state.remainingCounts(team...)
+	details.RemainingTiles = remainingTiles(counts)
+	details.OpenSpaces = c.state.openSpaces(counts)
+	details.DeadSpaces = c.state.board.deadSpaces()
+	message := c.state.message()
+	if c.puzzle != nil {
+		details.Puzzle = c.puzzle.status()
+		if c.puzzle.over() {
+			message = c.puzzle.message()
+		}
+	}
+	if len(c.state.partnerships) > 0 {
+		details.Partnerships = c.state.partnershipScores()
+	}
+	if c.solo != nil {
+		details.Solo = c.solo.status()
+		if c.solo.lost {
+			message = c.solo.message(c.state.teams[0])
+		}
 	}
+	if c.clock != nil {
+		details.Clock = c.clock.status(c.state.turn, c.over())
+	}
+	details.Message = message
 	var targets []*bg.BoardGameAction
-	if len(c.state.winners) == 0 && (len(team) == 0 || (len(team) == 1 && team[0] == c.state.turn)) {
+	if !c.over() && (len(team) == 0 || (len(team) == 1 && team[0] == c.state.turn)) {
 		targets = c.state.targets()
 	}
 	return &bg.BoardGameSnapshot{
@@ -144,40 +400,91 @@ func (c *Carcassonne) GetSnapshot(team ...string) (*bg.BoardGameSnapshot, error)
 		MoreData: details,
 		Targets:  targets,
 		Actions:  c.actions,
-		Message:  c.state.message(),
+		Message:  message.String(),
 	}, nil
 }
 
+// teams gets the teams the game was created with i.e. without the automa
+func (c *Carcassonne) teams() []string {
+	if c.solo != nil && c.solo.automa {
+		return c.state.teams[:1]
+	}
+	return c.state.teams
+}
+
 func (c *Carcassonne) GetBGN() *bgn.Game {
 	tags := map[string]string{
-		"Game":  key,
-		"Teams": strings.Join(c.state.teams, ", "),
-		"Seed":  fmt.Sprintf("%d", c.options.Seed),
+		"Game":    key,
+		"Teams":   strings.Join(c.teams(), ", "),
+		"Seed":    fmt.Sprintf("%d", c.options.Seed),
+		"Version": strconv.Itoa(bgnVersion),
+	}
+	if len(c.options.Deck) > 0 {
+		tags["Deck"] = encodeDeckOrder(c.options.Deck)
+	}
+	if c.options.Position != "" {
+		tags["Position"] = c.options.Position
+	}
+	if c.options.Goal != "" {
+		tags["Goal"] = c.options.Goal
+	}
+	if c.options.Solo != nil {
+		tags["Solo"] = encodeSolo(c.options.Solo)
+	}
+	if len(c.options.Partnerships) > 0 {
+		tags["Partnerships"] = encodePartnerships(c.state.teams, c.options.Partnerships)
+	}
+	if c.options.SharedMajority {
+		tags["SharedMajority"] = "true"
+	}
+	if c.options.Clock != nil {
+		tags["Clock"] = encodeClock(c.options.Clock)
+		if c.clock.options.Bank > 0 {
+			tags["ClockBanks"] = encodeClockBanks(c.teams(), c.clock.status(c.state.turn, c.over()).Remaining)
+		}
+	}
+	if c.options.ResignTokens != "" {
+		tags["ResignTokens"] = c.options.ResignTokens
+	}
+	if c.options.Visibility != "" {
+		tags["Visibility"] = c.options.Visibility
+	}
+	if c.options.Reveal > 0 {
+		tags["Reveal"] = strconv.Itoa(c.options.Reveal)
+	}
+	if c.options.HandSize > 1 {
+		tags["HandSize"] = strconv.Itoa(c.options.HandSize)
 	}
 	actions := make([]bgn.Action, 0)
 	for _, action := range c.actions {
-		bgnAction := bgn.Action{
+		actions = append(actions, bgn.Action{
 			TeamIndex: indexOf(c.state.teams, action.Team),
 			ActionKey: rune(actionToNotation[action.ActionType][0]),
-		}
-		switch action.ActionType {
-		case ActionPlaceTile:
-			var details PlaceTileActionDetails
-			_ = mapstructure.Decode(action.MoreDetails, &details)
-			bgnAction.Details = details.encodeBGN()
-		case ActionPlaceToken:
-			var details PlaceTokenActionDetails
-			_ = mapstructure.Decode(action.MoreDetails, &details)
-			bgnAction.Details = details.encodeBGN()
-		case bg.ActionSetWinners:
-			var details bg.SetWinnersActionDetails
-			_ = mapstructure.Decode(action.MoreDetails, &details)
-			bgnAction.Details, _ = details.EncodeBGN(c.state.teams)
-		}
-		actions = append(actions, bgnAction)
+			Details:   actionDetailsNotation(c.state.teams, action),
+		})
 	}
 	return &bgn.Game{
 		Tags:    tags,
 		Actions: actions,
 	}
 }
+
+// actionDetailsNotation encodes the details of an action as they are written in BGN
+func actionDetailsNotation(teams []string, action *bg.BoardGameAction) []string {
+	switch action.ActionType {
+	case ActionPlaceTile:
+		var details PlaceTileActionDetails
+		_ = mapstructure.Decode(action.MoreDetails, &details)
+		return details.encodeBGN()
+	case ActionPlaceToken:
+		var details PlaceTokenActionDetails
+		_ = mapstructure.Decode(action.MoreDetails, &details)
+		return details.encodeBGN()
+	case bg.ActionSetWinners:
+		var details bg.SetWinnersActionDetails
+		_ = mapstructure.Decode(action.MoreDetails, &details)
+		notation, _ := details.EncodeBGN(teams)
+		return notation
+	}
+	return nil
+}
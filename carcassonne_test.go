@@ -1,6 +1,8 @@
 package go_carcassonne
 
 import (
+	"encoding/json"
+	"math/rand"
 	"testing"
 	"time"
 
@@ -108,9 +110,97 @@ func Test_Carcassonne(t *testing.T) {
 
 	assert.Equal(t, 1, len(carcassonne.state.boardTokens))
 	assert.Equal(t, 3, carcassonne.state.scores[TeamB])
+	assert.Equal(t, 3, carcassonne.state.featureScores[TeamB][Road])
 	assert.Equal(t, []string{TeamB}, carcassonne.state.board.board[0].Teams[SideLeft])
 	assert.Equal(t, []string{TeamB}, carcassonne.state.board.board[0].Teams[SideRight])
 	assert.Equal(t, []string{TeamB}, carcassonne.state.board.board[1].Teams[SideLeft])
 	assert.Equal(t, []string{TeamB}, carcassonne.state.board.board[2].Teams[SideRight])
 	assert.Equal(t, TeamA, carcassonne.state.turn)
 }
+
+func Test_CarcassonneClone(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams: []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{
+			Seed: 123,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	random := rand.New(rand.NewSource(123))
+	for i := 0; i < 20; i++ {
+		if err := carcassonne.Do(randomAction(carcassonne.state, random)); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+
+	expected := snapshotJSON(t, carcassonne)
+	clone := carcassonne.Clone()
+	assert.Equal(t, expected, snapshotJSON(t, clone), "clone does not match original")
+
+	// play the clone to the end of the game and ensure the original is unchanged
+	for len(clone.state.winners) == 0 {
+		if err := clone.Do(randomAction(clone.state, random)); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	assert.Equal(t, expected, snapshotJSON(t, carcassonne), "original changed after modifying clone")
+	assert.NotEqual(t, expected, snapshotJSON(t, clone))
+
+	// the same actions on the original and a clone should lead to the same game including tile draws
+	clone = carcassonne.Clone()
+	for len(carcassonne.state.winners) == 0 {
+		action := randomAction(carcassonne.state, random)
+		if err := carcassonne.Do(action); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if err := clone.Do(action); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		assert.Equal(t, snapshotJSON(t, carcassonne), snapshotJSON(t, clone))
+	}
+}
+
+func Benchmark_CarcassonneClone(b *testing.B) {
+	carcassonne, _ := NewCarcassonne(&bg.BoardGameOptions{
+		Teams: []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{
+			Seed: 123,
+		},
+	})
+	random := rand.New(rand.NewSource(123))
+	for i := 0; i < 60; i++ {
+		_ = carcassonne.Do(randomAction(carcassonne.state, random))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		carcassonne.Clone()
+	}
+}
+
+// randomAction picks a random valid action for the current team
+func randomAction(s *state, random *rand.Rand) *bg.BoardGameAction {
+	actions, _ := s.moves(s.turn)
+	return actions[random.Intn(len(actions))]
+}
+
+func snapshotJSON(t *testing.T, carcassonne *Carcassonne) string {
+	snapshot, err := carcassonne.GetSnapshot()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	return string(raw)
+}
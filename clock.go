@@ -0,0 +1,254 @@
+package go_carcassonne
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	bg "github.com/quibbble/go-boardgame"
+)
+
+// Timeout outcomes
+const (
+	// TimeoutForfeit resigns the team that ran out of time
+	TimeoutForfeit = "Forfeit"
+
+	// TimeoutPass places the tile at random if it is not yet placed and passes placing a token
+	TimeoutPass = "Pass"
+
+	// TimeoutRandom places the tile and token at random
+	TimeoutRandom = "Random"
+)
+
+// Clock tells the time so games can be timed using a fake clock in tests
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the clock used unless another is set with SetClock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ClockOptions are the time controls of a game where every team starts with the same bank of time
+type ClockOptions struct {
+	// Bank is the total time each team has for all its turns, 0 for no bank
+	Bank time.Duration
+
+	// Increment is the time added to the bank of a team after each of its turns
+	Increment time.Duration
+
+	// MoveLimit is the most time a team may take for a single turn, 0 for no limit
+	MoveLimit time.Duration
+
+	// Timeout is what happens when a team runs out of time, TimeoutForfeit if empty
+	Timeout string
+}
+
+// ClockStatus is the time left for each team
+type ClockStatus struct {
+	Remaining     map[string]time.Duration // time left in the bank of each team
+	MoveRemaining time.Duration            // time left for the current turn when there is a move limit
+	TimedOut      []string                 // teams that ran out of time in the order they ran out
+}
+
+// clock times the turns of each team
+type clock struct {
+	options   ClockOptions
+	source    Clock
+	remaining map[string]time.Duration
+	turnStart time.Time
+	timedOut  []string
+}
+
+func newClock(options *ClockOptions, teams []string) (*clock, error) {
+	if options.Bank < 0 || options.Increment < 0 || options.MoveLimit < 0 {
+		return nil, fmt.Errorf("times cannot be negative")
+	} else if options.Bank == 0 && options.MoveLimit == 0 {
+		return nil, fmt.Errorf("a bank or a move limit is required")
+	}
+	c := &clock{
+		options:   *options,
+		source:    systemClock{},
+		remaining: make(map[string]time.Duration),
+		timedOut:  make([]string, 0),
+	}
+	if c.options.Timeout == "" {
+		c.options.Timeout = TimeoutForfeit
+	} else if c.options.Timeout != TimeoutForfeit && c.options.Timeout != TimeoutPass && c.options.Timeout != TimeoutRandom {
+		return nil, fmt.Errorf("got timeout %s but wanted %s, %s, or %s", c.options.Timeout, TimeoutForfeit, TimeoutPass, TimeoutRandom)
+	}
+	for _, team := range teams {
+		c.remaining[team] = options.Bank
+	}
+	c.turnStart = c.source.Now()
+	return c, nil
+}
+
+func (c *clock) clone() *clock {
+	remaining := make(map[string]time.Duration, len(c.remaining))
+	for team, duration := range c.remaining {
+		remaining[team] = duration
+	}
+	return &clock{
+		options:   c.options,
+		source:    c.source,
+		remaining: remaining,
+		turnStart: c.turnStart,
+		timedOut:  append(make([]string, 0, len(c.timedOut)), c.timedOut...),
+	}
+}
+
+// expired checks whether team has used up its bank or the move limit on its current turn
+func (c *clock) expired(team string) bool {
+	elapsed := c.source.Now().Sub(c.turnStart)
+	return (c.options.Bank > 0 && elapsed >= c.remaining[team]) ||
+		(c.options.MoveLimit > 0 && elapsed >= c.options.MoveLimit)
+}
+
+// endTurn takes the time used by team from its bank, adds the increment, and starts timing the next turn
+func (c *clock) endTurn(team string) {
+	now := c.source.Now()
+	if c.options.Bank > 0 {
+		c.remaining[team] = max(0, c.remaining[team]-now.Sub(c.turnStart)) + c.options.Increment
+	}
+	c.turnStart = now
+}
+
+func (c *clock) status(turn string, over bool) *ClockStatus {
+	elapsed := time.Duration(0)
+	if !over {
+		elapsed = c.source.Now().Sub(c.turnStart)
+	}
+	remaining := make(map[string]time.Duration, len(c.remaining))
+	for team, duration := range c.remaining {
+		if team == turn && c.options.Bank > 0 {
+			duration = max(0, duration-elapsed)
+		}
+		remaining[team] = duration
+	}
+	status := &ClockStatus{
+		Remaining: remaining,
+		TimedOut:  c.timedOut,
+	}
+	if c.options.MoveLimit > 0 {
+		status.MoveRemaining = max(0, c.options.MoveLimit-elapsed)
+	}
+	return status
+}
+
+// SetClock times the game with source from now on instead of the system clock
+func (c *Carcassonne) SetClock(source Clock) {
+	if c.clock == nil {
+		return
+	}
+	c.clock.source = source
+	c.clock.turnStart = source.Now()
+}
+
+// CheckClock handles the team whose turn it is running out of time
+// it is called before every action and should be called regularly by anything hosting a timed game
+func (c *Carcassonne) CheckClock() error {
+	if c.clock == nil || c.over() || !c.clock.expired(c.state.turn) {
+		return nil
+	}
+	team := c.state.turn
+	c.clock.timedOut = append(c.clock.timedOut, team)
+	if c.clock.options.Timeout == TimeoutForfeit {
+		if err := c.play(&bg.BoardGameAction{Team: team, ActionType: ActionResign}); err != nil {
+			return err
+		}
+		c.clock.endTurn(team)
+		return nil
+	}
+	random := rand.New(rand.NewSource(c.options.Seed + int64(len(c.actions))))
+	if c.state.playTiles[team] != nil {
+		moves, err := c.state.moves(team)
+		if err != nil {
+			return err
+		}
+		if err := c.play(moves[random.Intn(len(moves))]); err != nil {
+			return err
+		}
+	}
+	if !c.over() && c.state.turn == team && c.state.playTiles[team] == nil {
+		action := &bg.BoardGameAction{Team: team, ActionType: ActionPlaceToken, MoreDetails: PlaceTokenActionDetails{Pass: true}}
+		if c.clock.options.Timeout == TimeoutRandom {
+			moves, err := c.state.moves(team)
+			if err != nil {
+				return err
+			}
+			action = moves[random.Intn(len(moves))]
+		}
+		if err := c.play(action); err != nil {
+			return err
+		}
+	}
+	c.clock.endTurn(team)
+	return nil
+}
+
+// turnEnded checks whether action ended the turn of team whose turn it was before the action
+func (c *Carcassonne) turnEnded(team string, action *bg.BoardGameAction) bool {
+	if c.state.turn != team || c.over() {
+		return true
+	}
+	placed := action.ActionType == ActionPlaceTile || action.ActionType == ActionPlaceToken
+	return placed && c.state.playTiles[team] != nil
+}
+
+// encodeClock writes the Clock tag e.g. 5m0s/5s/0s/Forfeit
+func encodeClock(options *ClockOptions) string {
+	timeout := options.Timeout
+	if timeout == "" {
+		timeout = TimeoutForfeit
+	}
+	return strings.Join([]string{options.Bank.String(), options.Increment.String(), options.MoveLimit.String(), timeout}, "/")
+}
+
+func decodeClock(notation string) (*ClockOptions, error) {
+	fields := strings.Split(notation, "/")
+	if len(fields) != 4 {
+		return nil, loadFailure(fmt.Errorf("got %d but wanted 4 fields in clock", len(fields)))
+	}
+	durations := make([]time.Duration, 0, 3)
+	for _, field := range fields[:3] {
+		duration, err := time.ParseDuration(field)
+		if err != nil {
+			return nil, loadFailure(err)
+		}
+		durations = append(durations, duration)
+	}
+	return &ClockOptions{Bank: durations[0], Increment: durations[1], MoveLimit: durations[2], Timeout: fields[3]}, nil
+}
+
+// encodeClockBanks writes the ClockBanks tag with the time left for each team in the order of teams e.g. 4m35s/5m0s
+func encodeClockBanks(teams []string, remaining map[string]time.Duration) string {
+	banks := make([]string, 0, len(teams))
+	for _, team := range teams {
+		banks = append(banks, remaining[team].String())
+	}
+	return strings.Join(banks, "/")
+}
+
+// restoreBanks sets the time left for each team from the ClockBanks tag and starts timing the current turn
+func (c *clock) restoreBanks(teams []string, notation string) error {
+	fields := strings.Split(notation, "/")
+	if len(fields) != len(teams) {
+		return loadFailure(fmt.Errorf("got %d but wanted %d banks in clock banks", len(fields), len(teams)))
+	}
+	for i, field := range fields {
+		duration, err := time.ParseDuration(field)
+		if err != nil {
+			return loadFailure(err)
+		} else if duration < 0 {
+			return loadFailure(fmt.Errorf("bank of %s cannot be negative", teams[i]))
+		}
+		c.remaining[teams[i]] = duration
+	}
+	c.turnStart = c.source.Now()
+	return nil
+}
@@ -0,0 +1,173 @@
+package go_carcassonne
+
+import (
+	"testing"
+	"time"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a clock that only moves when told to
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) advance(d time.Duration) {
+	f.now = f.now.Add(d)
+}
+
+func newTimedGame(t *testing.T, options *ClockOptions) (*Carcassonne, *fakeClock) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 123, Clock: options},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	source := &fakeClock{now: time.Unix(0, 0)}
+	carcassonne.SetClock(source)
+	return carcassonne, source
+}
+
+// playTurn places the first legal tile and passes placing a token
+func playTurn(t *testing.T, carcassonne *Carcassonne) {
+	team := carcassonne.state.turn
+	moves, err := carcassonne.state.moves(team)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err := carcassonne.Do(moves[0]); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if carcassonne.state.turn == team {
+		if err := carcassonne.Do(&bg.BoardGameAction{Team: team, ActionType: ActionPlaceToken, MoreDetails: PlaceTokenActionDetails{Pass: true}}); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+}
+
+func Test_Clock_Increment(t *testing.T) {
+	carcassonne, source := newTimedGame(t, &ClockOptions{Bank: time.Minute, Increment: 5 * time.Second})
+	source.advance(20 * time.Second)
+	playTurn(t, carcassonne)
+	source.advance(10 * time.Second)
+
+	snapshot, err := carcassonne.GetSnapshot()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	status := snapshot.MoreData.(CarcassonneSnapshotData).Clock
+	assert.Equal(t, 45*time.Second, status.Remaining[TeamA])
+	assert.Equal(t, 50*time.Second, status.Remaining[TeamB])
+	assert.Empty(t, status.TimedOut)
+}
+
+func Test_Clock_Forfeit(t *testing.T) {
+	carcassonne, source := newTimedGame(t, &ClockOptions{Bank: time.Minute})
+	playTurn(t, carcassonne)
+	source.advance(time.Minute)
+
+	// the action is refused as the game ended when the team ran out of time
+	moves, _ := carcassonne.state.moves(TeamB)
+	assert.Error(t, carcassonne.Do(moves[0]))
+	assert.Equal(t, []string{TeamA}, carcassonne.state.winners)
+
+	snapshot, _ := carcassonne.GetSnapshot()
+	assert.Equal(t, []string{TeamB}, snapshot.MoreData.(CarcassonneSnapshotData).Clock.TimedOut)
+	assert.Equal(t, ActionResign, carcassonne.actions[len(carcassonne.actions)-1].ActionType)
+	assert.Equal(t, "5m0s/0s/0s/Forfeit", encodeClock(&ClockOptions{Bank: 5 * time.Minute}))
+}
+
+func Test_Clock_MoveLimit(t *testing.T) {
+	for _, timeout := range []string{TimeoutPass, TimeoutRandom} {
+		t.Run(timeout, func(t *testing.T) {
+			carcassonne, source := newTimedGame(t, &ClockOptions{MoveLimit: 30 * time.Second, Timeout: timeout})
+			placed := len(carcassonne.state.board.board)
+			source.advance(29 * time.Second)
+			assert.NoError(t, carcassonne.CheckClock())
+			assert.Equal(t, TeamA, carcassonne.state.turn)
+
+			source.advance(time.Second)
+			assert.NoError(t, carcassonne.CheckClock())
+			assert.Equal(t, TeamB, carcassonne.state.turn)
+			assert.Len(t, carcassonne.state.board.board, placed+1)
+			for _, action := range carcassonne.actions {
+				assert.Equal(t, TeamA, action.Team)
+			}
+			if timeout == TimeoutPass {
+				details := carcassonne.actions[len(carcassonne.actions)-1].MoreDetails
+				if token, ok := details.(PlaceTokenActionDetails); ok {
+					assert.True(t, token.Pass)
+				}
+			}
+
+			// the next team is given a full move
+			snapshot, _ := carcassonne.GetSnapshot()
+			assert.Equal(t, 30*time.Second, snapshot.MoreData.(CarcassonneSnapshotData).Clock.MoveRemaining)
+		})
+	}
+}
+
+func Test_Clock_BGN(t *testing.T) {
+	options := &ClockOptions{Bank: 5 * time.Minute, Increment: 5 * time.Second, Timeout: TimeoutRandom}
+	carcassonne, source := newTimedGame(t, options)
+	playTurn(t, carcassonne)
+	source.advance(6 * time.Minute)
+	assert.NoError(t, carcassonne.CheckClock())
+
+	game := carcassonne.GetBGN()
+	assert.Equal(t, "5m0s/5s/0s/Random", game.Tags["Clock"])
+	builder := Builder{}
+	loaded, err := builder.Load(game)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, options, loaded.(*Carcassonne).options.Clock)
+	assert.Equal(t, carcassonne.state.board.board, loaded.(*Carcassonne).state.board.board)
+
+	// the banks are kept rather than refilled by replaying the turns
+	assert.Equal(t, "5m5s/5s", game.Tags["ClockBanks"])
+	assert.Equal(t, map[string]time.Duration{TeamA: 5*time.Minute + 5*time.Second, TeamB: 5 * time.Second}, loaded.(*Carcassonne).clock.remaining)
+	game.Tags["ClockBanks"] = "5m5s"
+	_, err = builder.Load(game)
+	assert.Error(t, err)
+}
+
+func Test_Clock_SearchClone(t *testing.T) {
+	carcassonne, source := newTimedGame(t, &ClockOptions{Bank: time.Minute})
+	source.advance(time.Minute)
+
+	// simulated moves are not timed out however long the search takes
+	clone := carcassonne.searchClone()
+	moves, _ := clone.state.moves(TeamA)
+	assert.NoError(t, clone.Do(moves[0]))
+	assert.Empty(t, clone.state.winners)
+	assert.Empty(t, carcassonne.actions)
+
+	// while the game itself still times out
+	assert.NoError(t, carcassonne.CheckClock())
+	assert.Equal(t, []string{TeamB}, carcassonne.state.winners)
+}
+
+func Test_Clock_Invalid(t *testing.T) {
+	tests := []*ClockOptions{
+		{},
+		{Bank: -time.Second},
+		{Bank: time.Minute, Timeout: "Resign"},
+	}
+	for _, test := range tests {
+		_, err := NewCarcassonne(&bg.BoardGameOptions{Teams: []string{TeamA, TeamB}, MoreOptions: CarcassonneMoreOptions{Clock: test}})
+		assert.Error(t, err)
+	}
+}
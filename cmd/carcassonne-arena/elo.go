@@ -0,0 +1,119 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// eloBase is the average rating of all bots
+	eloBase = 1500
+
+	// eloIterations is the number of minorization-maximization steps used to fit ratings
+	eloIterations = 200
+)
+
+// result is the outcome of a single pairing between two bots where score is 1 for a win, 0.5 for a tie, and 0 for a loss of a
+type result struct {
+	a, b  int
+	score float64
+}
+
+// pairings splits a multiplayer game into head to head results between every pair of different bots
+func pairings(players []int, scores []int) []result {
+	results := make([]result, 0)
+	for i := 0; i < len(players); i++ {
+		for j := i + 1; j < len(players); j++ {
+			if players[i] == players[j] {
+				continue
+			}
+			score := 0.5
+			if scores[i] > scores[j] {
+				score = 1
+			} else if scores[i] < scores[j] {
+				score = 0
+			}
+			results = append(results, result{a: players[i], b: players[j], score: score})
+		}
+	}
+	return results
+}
+
+// elo fits Bradley-Terry strengths to results and converts them to Elo ratings centered on eloBase
+// a virtual draw between every pair of bots keeps ratings finite when a bot wins or loses every game
+func elo(bots int, results []result) []float64 {
+	wins := make([]float64, bots)
+	games := make([][]float64, bots)
+	for i := range games {
+		games[i] = make([]float64, bots)
+		for j := range games[i] {
+			if i != j {
+				wins[i] += 0.5
+				games[i][j] = 1
+			}
+		}
+	}
+	for _, r := range results {
+		wins[r.a] += r.score
+		wins[r.b] += 1 - r.score
+		games[r.a][r.b]++
+		games[r.b][r.a]++
+	}
+	gamma := make([]float64, bots)
+	for i := range gamma {
+		gamma[i] = 1
+	}
+	for iteration := 0; iteration < eloIterations; iteration++ {
+		next := make([]float64, bots)
+		for i := range gamma {
+			denominator := 0.
+			for j := range gamma {
+				if i != j {
+					denominator += games[i][j] / (gamma[i] + gamma[j])
+				}
+			}
+			next[i] = gamma[i]
+			if denominator > 0 {
+				next[i] = wins[i] / denominator
+			}
+		}
+		gamma = next
+	}
+	ratings := make([]float64, bots)
+	mean := 0.
+	for i, g := range gamma {
+		ratings[i] = 400 * math.Log10(g)
+		mean += ratings[i]
+	}
+	mean /= float64(bots)
+	for i := range ratings {
+		ratings[i] += eloBase - mean
+	}
+	return ratings
+}
+
+// eloInterval estimates a 95% confidence interval for each rating by resampling games with replacement
+func eloInterval(bots int, games [][]result, samples int, seed int64) (low, high []float64) {
+	random := rand.New(rand.NewSource(seed))
+	resampled := make([][]float64, bots)
+	for s := 0; s < samples; s++ {
+		results := make([]result, 0)
+		for range games {
+			results = append(results, games[random.Intn(len(games))]...)
+		}
+		for i, rating := range elo(bots, results) {
+			resampled[i] = append(resampled[i], rating)
+		}
+	}
+	low, high = make([]float64, bots), make([]float64, bots)
+	for i, ratings := range resampled {
+		if len(ratings) == 0 {
+			continue
+		}
+		sort.Float64s(ratings)
+		low[i] = ratings[int(0.025*float64(len(ratings)-1))]
+		high[i] = ratings[int(0.975*float64(len(ratings)-1))]
+	}
+	return low, high
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Elo(t *testing.T) {
+	games := make([][]result, 0)
+	for i := 0; i < 30; i++ {
+		score := 1.
+		if i%3 == 0 {
+			score = 0
+		}
+		games = append(games, pairings([]int{0, 1}, []int{int(score), 1 - int(score)}))
+	}
+	results := make([]result, 0)
+	for _, game := range games {
+		results = append(results, game...)
+	}
+	ratings := elo(2, results)
+	assert.InDelta(t, eloBase*2, ratings[0]+ratings[1], 0.001)
+	// winning two thirds of games is roughly a 120 point difference
+	assert.InDelta(t, 120, ratings[0]-ratings[1], 10)
+
+	low, high := eloInterval(2, games, 100, 0)
+	assert.True(t, low[0] <= ratings[0] && ratings[0] <= high[0])
+	assert.True(t, low[1] <= ratings[1] && ratings[1] <= high[1])
+
+	ties := elo(3, pairings([]int{0, 1, 2}, []int{10, 10, 10}))
+	for _, rating := range ties {
+		assert.InDelta(t, eloBase, rating, 0.001)
+	}
+}
@@ -0,0 +1,221 @@
+// Command carcassonne-arena plays registered bots against each other and reports how they compare
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	bg "github.com/quibbble/go-boardgame"
+	carcassonne "github.com/quibbble/go-carcassonne"
+)
+
+// features are the ways points are scored in the order they are reported
+var features = []string{carcassonne.City, carcassonne.Road, carcassonne.Cloister, carcassonne.Farm}
+
+// game is the outcome of a single arena game
+type game struct {
+	index   int
+	players []int
+	scores  []int
+	winners []int
+	feature []map[string]int
+	err     error
+}
+
+func main() {
+	botsFlag := flag.String("bots", "greedy,random", fmt.Sprintf("comma separated bots to seat, 2 to 5 of %s", strings.Join(carcassonne.BotNames(), ", ")))
+	games := flag.Int("games", 100, "number of games to play")
+	seed := flag.Int64("seed", 0, "seed of the first game, each following game uses the next seed")
+	parallel := flag.Int("parallel", runtime.NumCPU(), "number of games to play at the same time")
+	out := flag.String("out", "", "directory to save each game as BGN, empty to not save games")
+	iterations := flag.Int("mcts-iterations", 200, "search iterations per action for the mcts bot")
+	samples := flag.Int("samples", 200, "bootstrap samples used to estimate Elo confidence intervals")
+	flag.Parse()
+
+	carcassonne.RegisterBot("mcts", func(seed int64) carcassonne.Bot {
+		return carcassonne.NewMCTSBot(&carcassonne.MCTSBotOptions{Iterations: *iterations, Seed: seed})
+	})
+
+	names := strings.Split(*botsFlag, ",")
+	if len(names) < 2 || len(names) > 5 {
+		log.Fatalf("between 2 and 5 bots required but got %d", len(names))
+	}
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+		if _, err := carcassonne.NewBot(names[i], 0); err != nil {
+			log.Fatal(err)
+		}
+	}
+	// the same bot may be seated more than once so bots are rated by name
+	distinct := make([]string, 0)
+	index := make(map[string]int)
+	for _, name := range names {
+		if _, ok := index[name]; !ok {
+			index[name] = len(distinct)
+			distinct = append(distinct, name)
+		}
+	}
+	if len(distinct) < 2 {
+		log.Fatal("at least 2 different bots required")
+	}
+	if *out != "" {
+		if err := os.MkdirAll(*out, 0755); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *parallel < 1 {
+		*parallel = 1
+	}
+
+	jobs := make(chan int)
+	results := make([]*game, *games)
+	var wg sync.WaitGroup
+	for w := 0; w < *parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range jobs {
+				results[g] = play(g, names, index, *seed+int64(g), *out)
+			}
+		}()
+	}
+	for g := 0; g < *games; g++ {
+		jobs <- g
+	}
+	close(jobs)
+	wg.Wait()
+
+	report(os.Stdout, distinct, results, *samples, *seed)
+}
+
+// play plays a single game rotating seats so every bot moves first equally often
+func play(g int, names []string, index map[string]int, seed int64, out string) *game {
+	result := &game{index: g}
+	seats := len(names)
+	teams := make([]string, seats)
+	bots := make(map[string]carcassonne.Bot)
+	count := make(map[string]int)
+	for i := range teams {
+		name := names[(i+g)%seats]
+		count[name]++
+		team := name
+		if count[name] > 1 {
+			team = fmt.Sprintf("%s-%d", name, count[name])
+		}
+		teams[i] = team
+		bot, err := carcassonne.NewBot(name, seed+int64(i))
+		if err != nil {
+			result.err = err
+			return result
+		}
+		bots[team] = bot
+		result.players = append(result.players, index[name])
+	}
+	carc, err := carcassonne.NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       teams,
+		MoreOptions: carcassonne.CarcassonneMoreOptions{Seed: seed},
+	})
+	if err != nil {
+		result.err = err
+		return result
+	}
+	snapshot, err := carc.GetSnapshot()
+	for err == nil && len(snapshot.Winners) == 0 {
+		var action *bg.BoardGameAction
+		if action, err = bots[snapshot.Turn].Action(carc, snapshot.Turn); err != nil {
+			break
+		}
+		if err = carc.Do(action); err != nil {
+			break
+		}
+		snapshot, err = carc.GetSnapshot()
+	}
+	if err != nil {
+		result.err = fmt.Errorf("game %d: %w", g, err)
+		return result
+	}
+	if out != "" {
+		path := filepath.Join(out, fmt.Sprintf("game-%04d.bgn", g))
+		if err := os.WriteFile(path, []byte(carc.GetBGN().String()), 0644); err != nil {
+			result.err = err
+			return result
+		}
+	}
+	data := snapshot.MoreData.(carcassonne.CarcassonneSnapshotData)
+	for i, team := range teams {
+		result.scores = append(result.scores, data.Scores[team])
+		result.feature = append(result.feature, data.FeatureScores[team])
+		for _, winner := range snapshot.Winners {
+			if winner == team {
+				result.winners = append(result.winners, i)
+			}
+		}
+	}
+	return result
+}
+
+// report writes win rates, average scores, and Elo ratings for every bot
+func report(w io.Writer, bots []string, games []*game, samples int, seed int64) {
+	wins := make([]float64, len(bots))
+	seats := make([]int, len(bots))
+	scores := make([]int, len(bots))
+	feature := make([]map[string]int, len(bots))
+	for i := range feature {
+		feature[i] = make(map[string]int)
+	}
+	pairs := make([][]result, 0)
+	played := 0
+	for _, g := range games {
+		if g.err != nil {
+			fmt.Fprintln(os.Stderr, g.err)
+			continue
+		}
+		played++
+		for i, player := range g.players {
+			seats[player]++
+			scores[player] += g.scores[i]
+			for _, f := range features {
+				feature[player][f] += g.feature[i][f]
+			}
+		}
+		// ties split the win between all winners
+		for _, winner := range g.winners {
+			wins[g.players[winner]] += 1 / float64(len(g.winners))
+		}
+		pairs = append(pairs, pairings(g.players, g.scores))
+	}
+	if played == 0 {
+		fmt.Fprintln(w, "no games played")
+		return
+	}
+	results := make([]result, 0)
+	for _, pair := range pairs {
+		results = append(results, pair...)
+	}
+	ratings := elo(len(bots), results)
+	low, high := eloInterval(len(bots), pairs, samples, seed)
+
+	fmt.Fprintf(w, "%d games played\n\n", played)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "bot\tgames\twin rate\tavg score\t%s\telo\t95%% ci\t\n", strings.Join(features, "\t"))
+	for i, bot := range bots {
+		if seats[i] == 0 {
+			continue
+		}
+		n := float64(seats[i])
+		fmt.Fprintf(tw, "%s\t%d\t%.1f%%\t%.1f\t", bot, seats[i], 100*wins[i]/n, float64(scores[i])/n)
+		for _, f := range features {
+			fmt.Fprintf(tw, "%.1f\t", float64(feature[i][f])/n)
+		}
+		fmt.Fprintf(tw, "%.0f\t%.0f-%.0f\t\n", ratings[i], low[i], high[i])
+	}
+	tw.Flush()
+}
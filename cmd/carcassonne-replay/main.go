@@ -0,0 +1,51 @@
+// Command carcassonne-replay draws a game saved as BGN as an animated GIF or the final board as a PNG
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/quibbble/go-boardgame/pkg/bgn"
+	carcassonne "github.com/quibbble/go-carcassonne"
+)
+
+func main() {
+	in := flag.String("in", "", "BGN file of the game to draw")
+	out := flag.String("out", "replay.gif", "file to write, a .png file draws only the final board")
+	scale := flag.Float64("scale", 1, "size of each tile as a multiple of 64 pixels")
+	delay := flag.Int("delay", 50, "time each frame is shown in hundredths of a second")
+	endDelay := flag.Int("end-delay", 400, "time the final frame is shown in hundredths of a second")
+	flag.Parse()
+
+	if *in == "" {
+		log.Fatal("a BGN file is required")
+	}
+	raw, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	game, err := bgn.Parse(string(raw))
+	if err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	if strings.EqualFold(filepath.Ext(*out), ".png") {
+		loaded, err := (&carcassonne.Builder{}).Load(game)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = loaded.(*carcassonne.Carcassonne).RenderPNG(f, *scale)
+	} else {
+		err = carcassonne.RenderReplayGIF(f, game, &carcassonne.ReplayOptions{Scale: *scale, Delay: *delay, EndDelay: *endDelay})
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,27 @@
+// Command carcassonne-server hosts Carcassonne games over HTTP and WebSockets saving every game as BGN
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	dir := flag.String("dir", "games", "directory where games are saved as BGN and loaded from on start")
+	takeover := flag.String("takeover", "", "bot that plays for teams while their players are disconnected and another player is connected, empty to wait for them")
+	clock := flag.Duration("clock", time.Second, "how often the clocks of timed games are checked so teams time out without waiting for the next action")
+	flag.Parse()
+
+	s, err := newServer(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.takeover = *takeover
+	go s.tickClocks(*clock)
+	log.Printf("loaded %d games from %s", len(s.games), *dir)
+	log.Printf("listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, s))
+}
@@ -0,0 +1,555 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+	"github.com/quibbble/go-boardgame/pkg/bgn"
+	carcassonne "github.com/quibbble/go-carcassonne"
+)
+
+// server hosts games that are saved to dir as BGN after every change
+type server struct {
+	builder  *carcassonne.Builder
+	dir      string
+	upgrader websocket.Upgrader
+
+	// takeover is the name of the bot that plays for a team while none of its players are connected, empty to wait for them
+	// seats are only taken over while a player of another team is connected so bots never play a game on their own
+	takeover string
+
+	mu    sync.Mutex
+	games map[string]*game
+}
+
+// game is a hosted game which is saved after every action
+type game struct {
+	id   string
+	game *carcassonne.SafeCarcassonne
+
+	// saving is held while saving so saves never overlap
+	saving sync.Mutex
+
+	// players is the number of websocket connections of each team
+	mu      sync.Mutex
+	players map[string]int
+
+	// bots is the name of the bot playing for each team that was taken over and is saved with the game
+	bots map[string]string
+
+	// waiting is the name of the bot for each team whose players left while no other player was connected
+	// the seat is taken over once a player connects so bots never play a game on their own
+	waiting map[string]string
+
+	// timed is set when the game has a clock that has to be checked
+	timed bool
+}
+
+// conn is a websocket connection where writes are serialized as only one writer is allowed at a time
+type conn struct {
+	mu sync.Mutex
+	ws *websocket.Conn
+}
+
+// message is sent to websocket connections with either a snapshot or an error
+type message struct {
+	Snapshot *bg.BoardGameSnapshot `json:",omitempty"`
+	Error    string                `json:",omitempty"`
+
+	// Reason, Violation, and Message explain errors caused by moves that break the rules
+	Reason    string                    `json:",omitempty"`
+	Violation carcassonne.RuleViolation `json:",omitempty"`
+	Message   *carcassonne.Message      `json:",omitempty"`
+}
+
+// errorMessage creates the message for err including the rule violation behind it if any
+func errorMessage(err error) *message {
+	m := &message{Error: err.Error()}
+	if violation, ok := carcassonne.AsRuleViolation(err); ok {
+		m.Reason = violation.Reason()
+		m.Violation = violation
+		m.Message = violation.Message()
+	}
+	return m
+}
+
+// botsTag is the BGN tag listing the bot playing for each team that was taken over like TeamB:greedy, TeamC:random
+const botsTag = "Bots"
+
+// createRequest is the body used to create a game
+type createRequest struct {
+	// ID of the game, a random ID is used when empty
+	ID string
+	bg.BoardGameOptions
+}
+
+// newServer creates a server loading every game saved in dir
+func newServer(dir string) (*server, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	s := &server{
+		builder: &carcassonne.Builder{},
+		dir:     dir,
+		games:   make(map[string]*game),
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.bgn"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := bgn.Parse(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		loaded, err := s.builder.Load(parsed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		id := strings.TrimSuffix(filepath.Base(path), ".bgn")
+		g := newGame(id, loaded.(*carcassonne.Carcassonne))
+		if err := g.restoreBots(parsed.Tags[botsTag]); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		s.games[id] = g
+	}
+	return s, nil
+}
+
+// restoreBots has the bots that played for teams when the game was saved wait to take over their seats again
+// no player is connected after loading so the seats are only taken over once one connects
+func (g *game) restoreBots(tag string) error {
+	if tag == "" {
+		return nil
+	}
+	for _, seat := range strings.Split(tag, ", ") {
+		idx := strings.LastIndex(seat, ":")
+		if idx < 0 {
+			return fmt.Errorf("got bot %s but wanted team:bot", seat)
+		}
+		team, name := seat[:idx], seat[idx+1:]
+		if _, err := carcassonne.NewBot(name, 0); err != nil {
+			return err
+		}
+		if _, err := g.game.GetSnapshot(team); err != nil {
+			return err
+		}
+		g.waiting[team] = name
+	}
+	return nil
+}
+
+// ServeHTTP routes
+//
+//	GET  /games                  list game IDs
+//	POST /games                  create a game
+//	GET  /games/{id}?team=       get the snapshot seen by team
+//	GET  /games/{id}/bgn         get the game as BGN
+//	GET  /games/{id}/ws?team=    play or watch over a websocket
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "games" || len(parts) > 3 {
+		http.NotFound(w, r)
+		return
+	}
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			s.list(w)
+		case http.MethodPost:
+			s.create(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+	g := s.game(parts[1])
+	if g == nil {
+		http.Error(w, fmt.Sprintf("game %s not found", parts[1]), http.StatusNotFound)
+		return
+	}
+	route := ""
+	if len(parts) == 3 {
+		route = parts[2]
+	}
+	switch {
+	case route == "" && r.Method == http.MethodGet:
+		raw, err := g.snapshot(r.URL.Query().Get("team"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeRaw(w, http.StatusOK, raw)
+	case route == "bgn" && r.Method == http.MethodGet:
+		raw := g.game.GetBGN().String()
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(raw))
+	case route == "ws" && r.Method == http.MethodGet:
+		s.connect(w, r, g)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newGame(id string, c *carcassonne.Carcassonne) *game {
+	_, timed := c.GetBGN().Tags["Clock"]
+	return &game{
+		id:      id,
+		game:    carcassonne.WrapCarcassonne(c),
+		players: make(map[string]int),
+		bots:    make(map[string]string),
+		waiting: make(map[string]string),
+		timed:   timed,
+	}
+}
+
+func (s *server) game(id string) *game {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.games[id]
+}
+
+func (s *server) list(w http.ResponseWriter) {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.games))
+	for id := range s.games {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+	sort.Strings(ids)
+	writeJSON(w, http.StatusOK, ids)
+}
+
+func (s *server) create(w http.ResponseWriter, r *http.Request) {
+	var request createRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.ID == "" {
+		request.ID = randomID()
+	}
+	if strings.ContainsAny(request.ID, `/\.`) {
+		http.Error(w, fmt.Sprintf("invalid game id %s", request.ID), http.StatusBadRequest)
+		return
+	}
+	created, err := s.builder.CreateWithBGN(&request.BoardGameOptions)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	g := newGame(request.ID, created.(*carcassonne.Carcassonne))
+	s.mu.Lock()
+	if _, ok := s.games[request.ID]; ok {
+		s.mu.Unlock()
+		http.Error(w, fmt.Sprintf("game %s already exists", request.ID), http.StatusConflict)
+		return
+	}
+	s.games[request.ID] = g
+	s.mu.Unlock()
+	if err := s.save(g); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"ID": request.ID})
+}
+
+// do performs action then saves the game, connections are sent the new snapshot by their subscriptions
+func (s *server) do(g *game, action *bg.BoardGameAction) ([]byte, error) {
+	if err := g.game.Do(action); err != nil {
+		return nil, err
+	}
+	if err := s.save(g); err != nil {
+		return nil, err
+	}
+	return g.snapshot(action.Team)
+}
+
+// tickClocks checks the clocks of every timed game each interval so teams time out without waiting for the next action
+func (s *server) tickClocks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.checkClocks()
+	}
+}
+
+// checkClocks handles teams that ran out of time in every timed game and saves the games that changed
+func (s *server) checkClocks() {
+	s.mu.Lock()
+	games := make([]*game, 0, len(s.games))
+	for _, g := range s.games {
+		if g.timed {
+			games = append(games, g)
+		}
+	}
+	s.mu.Unlock()
+	for _, g := range games {
+		actions := len(g.game.GetBGN().Actions)
+		if err := g.game.CheckClock(); err != nil {
+			log.Printf("failed to check the clock of game %s: %s", g.id, err)
+			continue
+		}
+		if len(g.game.GetBGN().Actions) != actions {
+			if err := s.save(g); err != nil {
+				log.Printf("failed to save game %s: %s", g.id, err)
+			}
+		}
+	}
+}
+
+// snapshot encodes what team sees where an empty team sees everything
+func (g *game) snapshot(team string) ([]byte, error) {
+	snapshot, err := g.game.GetSnapshot(teams(team)...)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(snapshot)
+}
+
+// save writes the game to a temporary file then renames it so a crash never leaves a partial game
+func (s *server) save(g *game) error {
+	g.saving.Lock()
+	defer g.saving.Unlock()
+	path := filepath.Join(s.dir, g.id+".bgn")
+	if err := os.WriteFile(path+".tmp", []byte(g.bgn().String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// bgn gets the game as BGN including the bots playing or waiting to play for teams that were taken over
+func (g *game) bgn() *bgn.Game {
+	game := g.game.GetBGN()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.bots)+len(g.waiting) > 0 {
+		seats := make([]string, 0, len(g.bots)+len(g.waiting))
+		for team, name := range g.bots {
+			seats = append(seats, team+":"+name)
+		}
+		for team, name := range g.waiting {
+			seats = append(seats, team+":"+name)
+		}
+		sort.Strings(seats)
+		game.Tags[botsTag] = strings.Join(seats, ", ")
+	}
+	return game
+}
+
+// connect upgrades to a websocket which receives a snapshot after every action and may send actions for its team
+func (s *server) connect(w http.ResponseWriter, r *http.Request, g *game) {
+	team := r.URL.Query().Get("team")
+	subscription, err := g.game.Subscribe(teams(team)...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer subscription.Unsubscribe()
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	if team != "" {
+		s.join(g, team)
+		defer s.leave(g, team)
+	}
+	c := &conn{ws: ws}
+	defer c.ws.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for snapshot := range subscription.C {
+			if err := c.write(&message{Snapshot: snapshot}); err != nil {
+				return
+			}
+		}
+	}()
+	for {
+		_, raw, err := ws.ReadMessage()
+		if err != nil {
+			break
+		}
+		var action bg.BoardGameAction
+		if err := json.Unmarshal(raw, &action); err != nil {
+			_ = c.write(&message{Error: err.Error()})
+			continue
+		}
+		if team == "" {
+			_ = c.write(&message{Error: "spectators cannot perform actions"})
+			continue
+		}
+		// connections may only act for the team they joined as
+		action.Team = team
+		if _, err := s.do(g, &action); err != nil {
+			_ = c.write(errorMessage(err))
+		}
+	}
+	// stop the writer before the connection is closed
+	subscription.Unsubscribe()
+	<-done
+}
+
+// join hands the seat of team back to its player if a bot took it over
+// and has bots take over the seats of teams whose players left while no one else was connected
+func (s *server) join(g *game, team string) {
+	if s.joinSeat(g, team) {
+		s.saveSeats(g)
+	}
+}
+
+// leave has a bot take over the seat of team once its last player disconnects
+// when no other player is connected the seat waits for one to connect so bots never play the game on their own
+func (s *server) leave(g *game, team string) {
+	if s.leaveSeat(g, team) {
+		s.saveSeats(g)
+	}
+}
+
+// joinSeat updates the seats for a player of team connecting and returns whether any seat changed
+func (s *server) joinSeat(g *game, team string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.players[team]++
+	_, changed := g.waiting[team]
+	delete(g.waiting, team)
+	if _, ok := g.bots[team]; ok {
+		if err := g.game.HandBack(team); err != nil {
+			log.Printf("failed to hand %s back in game %s: %s", team, g.id, err)
+		} else {
+			delete(g.bots, team)
+			changed = true
+		}
+	}
+	waiting := make([]string, 0, len(g.waiting))
+	for waitingTeam := range g.waiting {
+		waiting = append(waiting, waitingTeam)
+	}
+	sort.Strings(waiting)
+	for _, waitingTeam := range waiting {
+		name := g.waiting[waitingTeam]
+		delete(g.waiting, waitingTeam)
+		changed = s.takeOver(g, waitingTeam, name) || changed
+	}
+	return changed
+}
+
+// leaveSeat updates the seats for a player of team disconnecting and returns whether any seat changed
+func (s *server) leaveSeat(g *game, team string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.players[team]--
+	if g.players[team] > 0 || s.takeover == "" {
+		return false
+	}
+	for _, players := range g.players {
+		if players > 0 {
+			return s.takeOver(g, team, s.takeover)
+		}
+	}
+	g.waiting[team] = s.takeover
+	return true
+}
+
+// takeOver has the bot called name play for team, only called while the players lock is held
+func (s *server) takeOver(g *game, team, name string) bool {
+	bot, err := carcassonne.NewBot(name, 0)
+	if err != nil {
+		log.Printf("failed to create bot for %s in game %s: %s", team, g.id, err)
+		return false
+	}
+	if err := g.game.TakeOver(team, bot); err != nil {
+		log.Printf("failed to take over %s in game %s: %s", team, g.id, err)
+		return false
+	}
+	g.bots[team] = name
+	return true
+}
+
+// saveSeats saves g after its seats changed
+func (s *server) saveSeats(g *game) {
+	if err := s.save(g); err != nil {
+		log.Printf("failed to save game %s: %s", g.id, err)
+	}
+}
+
+func contains(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *conn) write(m *message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ws.WriteJSON(m)
+}
+
+// teams gets the arguments for getting what team sees where an empty team sees everything
+func teams(team string) []string {
+	if team == "" {
+		return nil
+	}
+	return []string{team}
+}
+
+func randomID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func writeRaw(w http.ResponseWriter, status int, raw []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(raw)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err using the HTTP status that best matches its game status
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	var gameErr *bgerr.Error
+	if errors.As(err, &gameErr) {
+		switch gameErr.Status {
+		case bgerr.StatusUnknownTeam:
+			status = http.StatusNotFound
+		case bgerr.StatusWrongTurn, bgerr.StatusGameOver:
+			status = http.StatusConflict
+		case bgerr.StatusBGNEncodingFailure:
+			status = http.StatusInternalServerError
+		}
+	} else if errors.Is(err, os.ErrPermission) || errors.Is(err, os.ErrNotExist) {
+		status = http.StatusInternalServerError
+	}
+	http.Error(w, err.Error(), status)
+}
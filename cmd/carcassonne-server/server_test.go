@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	bg "github.com/quibbble/go-boardgame"
+	carcassonne "github.com/quibbble/go-carcassonne"
+	"github.com/stretchr/testify/assert"
+)
+
+// received is a websocket message with the snapshot decoded enough to check
+type received struct {
+	Snapshot *struct {
+		Turn    string
+		Actions []*bg.BoardGameAction
+	}
+	Error     string
+	Reason    string
+	Violation map[string]interface{}
+	Message   *carcassonne.Message
+}
+
+func dial(t *testing.T, url, team string) *websocket.Conn {
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http")+"/games/test/ws?team="+team, nil)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	return ws
+}
+
+func read(t *testing.T, ws *websocket.Conn) *received {
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var m received
+	if err := ws.ReadJSON(&m); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	return &m
+}
+
+func Test_Server(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newServer(dir)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	ts := httptest.NewServer(s)
+
+	resp, err := http.Post(ts.URL+"/games", "application/json", strings.NewReader(`{"ID": "test", "Teams": ["TeamA", "TeamB"], "MoreOptions": {"Seed": 5}}`))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	_ = resp.Body.Close()
+
+	a, b, spectator := dial(t, ts.URL, "TeamA"), dial(t, ts.URL, "TeamB"), dial(t, ts.URL, "")
+	for _, ws := range []*websocket.Conn{a, b, spectator} {
+		assert.Equal(t, "TeamA", read(t, ws).Snapshot.Turn)
+	}
+
+	// the wrong team cannot act and only hears about its own error
+	assert.NoError(t, b.WriteJSON(&bg.BoardGameAction{
+		ActionType:  carcassonne.ActionPlaceToken,
+		MoreDetails: carcassonne.PlaceTokenActionDetails{Pass: true},
+	}))
+	assert.NotEmpty(t, read(t, b).Error)
+
+	// moves that break the rules say why
+	assert.NoError(t, a.WriteJSON(&bg.BoardGameAction{
+		ActionType:  carcassonne.ActionPlaceToken,
+		MoreDetails: carcassonne.PlaceTokenActionDetails{Pass: true},
+	}))
+	m := read(t, a)
+	assert.Equal(t, carcassonne.ReasonWrongPhase, m.Reason)
+	assert.Equal(t, carcassonne.ActionPlaceTile, m.Violation["Phase"])
+	assert.Equal(t, "TeamA debe colocar loseta y no colocar seguidor", m.Message.Localize(carcassonne.Spanish))
+
+	// a tile placed over the websocket is pushed to every connection
+	// a local copy of the game picks a valid placement as it has the same deck
+	local, err := carcassonne.NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{"TeamA", "TeamB"},
+		MoreOptions: carcassonne.CarcassonneMoreOptions{Seed: 5},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	place, err := carcassonne.NewRandomBot(0).Action(local, "TeamA")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.NoError(t, a.WriteJSON(place))
+	for _, ws := range []*websocket.Conn{a, b, spectator} {
+		assert.Len(t, read(t, ws).Snapshot.Actions, 1)
+	}
+
+	// actions cannot be sent over HTTP as anyone could act for any team
+	body, _ := json.Marshal(&bg.BoardGameAction{
+		Team:        "TeamA",
+		ActionType:  carcassonne.ActionPlaceToken,
+		MoreDetails: carcassonne.PlaceTokenActionDetails{Pass: true},
+	})
+	resp, err = http.Post(ts.URL+"/games/test/actions", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = resp.Body.Close()
+	assert.NoError(t, a.WriteJSON(&bg.BoardGameAction{
+		ActionType:  carcassonne.ActionPlaceToken,
+		MoreDetails: carcassonne.PlaceTokenActionDetails{Pass: true},
+	}))
+	for _, ws := range []*websocket.Conn{a, b, spectator} {
+		m := read(t, ws)
+		assert.Equal(t, "TeamB", m.Snapshot.Turn)
+		assert.Len(t, m.Snapshot.Actions, 2)
+	}
+	saved := get(t, ts.URL+"/games/test/bgn")
+	_ = a.Close()
+	_ = b.Close()
+	_ = spectator.Close()
+	ts.Close()
+
+	// a new server loads the game saved to disk
+	s, err = newServer(dir)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	ts = httptest.NewServer(s)
+	defer ts.Close()
+	assert.Equal(t, "[\"test\"]\n", get(t, ts.URL+"/games"))
+	reloaded := get(t, ts.URL+"/games/test/bgn")
+	assert.Contains(t, reloaded, `[Seed "5"]`)
+	// tags are written in any order so only the actions are compared
+	assert.Equal(t, saved[strings.Index(saved, "\n\n"):], reloaded[strings.Index(reloaded, "\n\n"):])
+	assert.Equal(t, "TeamB", read(t, dial(t, ts.URL, "TeamB")).Snapshot.Turn)
+
+	resp, err = http.Get(ts.URL + "/games/test/ws?team=TeamC")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = resp.Body.Close()
+}
+
+func get(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	return string(raw)
+}
+
+func Test_Server_TakeOver(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newServer(dir)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	s.takeover = "random"
+	ts := httptest.NewServer(s)
+	resp, err := http.Post(ts.URL+"/games", "application/json", strings.NewReader(`{"ID": "test", "Teams": ["TeamA", "TeamB"], "MoreOptions": {"Seed": 5}}`))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	_ = resp.Body.Close()
+	g := s.game("test")
+
+	// a bot takes over once the last connection of a team closes while another player is connected
+	a := dial(t, ts.URL, "TeamA")
+	read(t, a)
+	b := dial(t, ts.URL, "TeamB")
+	read(t, b)
+	_ = b.Close()
+	waitFor(t, g, func() bool { return contains(g.game.BotTeams(), "TeamB") })
+
+	// the bot plays its turn right after the player's turn
+	local := g.game.Clone()
+	place, err := carcassonne.NewRandomBot(0).Action(local, "TeamA")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if _, err := s.do(g, place); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	snapshot, _ := g.game.GetSnapshot()
+	if snapshot.Turn == "TeamA" && len(snapshot.Actions) == 1 {
+		if _, err := s.do(g, &bg.BoardGameAction{Team: "TeamA", ActionType: carcassonne.ActionPlaceToken, MoreDetails: carcassonne.PlaceTokenActionDetails{Pass: true}}); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		snapshot, _ = g.game.GetSnapshot()
+	}
+	assert.Equal(t, "TeamA", snapshot.Turn)
+	assert.Equal(t, "TeamB", snapshot.Actions[len(snapshot.Actions)-1].Team)
+
+	// the seat is handed back when the player returns
+	b = dial(t, ts.URL, "TeamB")
+	read(t, b)
+	waitFor(t, g, func() bool { return len(g.game.BotTeams()) == 0 })
+	_ = b.Close()
+	waitFor(t, g, func() bool { return contains(g.game.BotTeams(), "TeamB") })
+
+	// the last player to leave is not taken over so bots never play on their own
+	actions := len(snapshot.Actions)
+	_ = a.Close()
+	waitFor(t, g, func() bool { return g.players["TeamA"] == 0 })
+	assert.Equal(t, []string{"TeamB"}, g.game.BotTeams())
+	snapshot, _ = g.game.GetSnapshot()
+	assert.Len(t, snapshot.Actions, actions)
+
+	// the seat is taken over once another player connects
+	b = dial(t, ts.URL, "TeamB")
+	read(t, b)
+	waitFor(t, g, func() bool { return contains(g.game.BotTeams(), "TeamA") })
+	assert.Equal(t, []string{"TeamA"}, g.game.BotTeams())
+	snapshot, _ = g.game.GetSnapshot()
+	assert.Equal(t, "TeamB", snapshot.Turn)
+	_ = b.Close()
+	waitFor(t, g, func() bool { return g.players["TeamB"] == 0 })
+	ts.Close()
+
+	// seats taken over and seats waiting to be taken over are saved with the game
+	raw, err := os.ReadFile(filepath.Join(dir, "test.bgn"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Contains(t, string(raw), `[Bots "TeamA:random, TeamB:random"]`)
+
+	// loading does not take over any seat until a player connects
+	snapshot, _ = g.game.GetSnapshot()
+	actions = len(snapshot.Actions)
+	s, err = newServer(dir)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	g = s.game("test")
+	assert.Empty(t, g.game.BotTeams())
+	snapshot, _ = g.game.GetSnapshot()
+	assert.Len(t, snapshot.Actions, actions)
+	ts = httptest.NewServer(s)
+	defer ts.Close()
+	b = dial(t, ts.URL, "TeamB")
+	read(t, b)
+	waitFor(t, g, func() bool { return contains(g.game.BotTeams(), "TeamA") })
+	assert.Equal(t, []string{"TeamA"}, g.game.BotTeams())
+	_ = b.Close()
+}
+
+// waitFor waits for the server to catch up with a connection opening or closing
+// the players lock is held while seats change so done is only checked once a change is complete
+func waitFor(t *testing.T, g *game, done func() bool) {
+	check := func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return done()
+	}
+	for deadline := time.Now().Add(5 * time.Second); !check(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Error("timed out waiting")
+			t.FailNow()
+		}
+	}
+}
+
+func Test_Server_Clock(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newServer(dir)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	resp, err := http.Post(ts.URL+"/games", "application/json", strings.NewReader(`{"ID": "test", "Teams": ["TeamA", "TeamB"], "MoreOptions": {"Seed": 5, "Clock": {"MoveLimit": 10000000, "Timeout": "Pass"}}}`))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	_ = resp.Body.Close()
+
+	// the turn times out without another action once the clock is checked
+	time.Sleep(20 * time.Millisecond)
+	s.checkClocks()
+	snapshot, _ := s.game("test").game.GetSnapshot()
+	assert.Equal(t, "TeamB", snapshot.Turn)
+	raw, err := os.ReadFile(filepath.Join(dir, "test.bgn"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	saved, current := string(raw), s.game("test").bgn().String()
+	assert.Equal(t, current[strings.Index(current, "\n\n"):], saved[strings.Index(saved, "\n\n"):])
+}
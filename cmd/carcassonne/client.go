@@ -0,0 +1,407 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgn"
+	carcassonne "github.com/quibbble/go-carcassonne"
+)
+
+const help = `commands:
+  rotate [left|right]     rotate the play tile, right by default
+  place X Y               place the play tile at X,Y
+  token SIDE [TYPE]       place a token on the last placed tile, SIDE is a side, farm side, or center
+  pass                    do not place a token
+  moves                   list every valid action
+  bot TEAM NAME           seat a bot for TEAM, one of %s
+  human TEAM              give TEAM back to a person
+  new SEED TEAM TEAM...   start a new game
+  load FILE               load a game from a BGN file
+  save FILE               save the game as BGN
+  show                    draw the board again
+  help                    show this help
+  quit                    exit
+`
+
+// client holds a game being played in the terminal along with the bots seated in it
+type client struct {
+	out      io.Writer
+	renderer *renderer
+	game     *carcassonne.Carcassonne
+	bots     map[string]carcassonne.Bot
+	names    map[string]string
+}
+
+func newClient(out io.Writer, renderer *renderer) *client {
+	return &client{
+		out:      out,
+		renderer: renderer,
+		bots:     make(map[string]carcassonne.Bot),
+		names:    make(map[string]string),
+	}
+}
+
+func (c *client) newGame(teams []string, seed int64) error {
+	for i := range teams {
+		teams[i] = strings.TrimSpace(teams[i])
+	}
+	game, err := carcassonne.NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       teams,
+		MoreOptions: carcassonne.CarcassonneMoreOptions{Seed: seed},
+	})
+	if err != nil {
+		return err
+	}
+	c.setGame(game)
+	return nil
+}
+
+func (c *client) setGame(game *carcassonne.Carcassonne) {
+	c.game = game
+	c.bots = make(map[string]carcassonne.Bot)
+	c.names = make(map[string]string)
+}
+
+func (c *client) load(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	parsed, err := bgn.Parse(string(raw))
+	if err != nil {
+		return err
+	}
+	game, err := (&carcassonne.Builder{}).Load(parsed)
+	if err != nil {
+		return err
+	}
+	c.setGame(game.(*carcassonne.Carcassonne))
+	return nil
+}
+
+func (c *client) save(path string) error {
+	return os.WriteFile(path, []byte(c.game.GetBGN().String()), 0644)
+}
+
+// seat has a bot play for team
+func (c *client) seat(team, name string) error {
+	snapshot, err := c.game.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	if !contains(snapshot.Teams, team) {
+		return fmt.Errorf("unknown team %s", team)
+	}
+	bot, err := carcassonne.NewBot(name, int64(len(c.game.GetBGN().Actions)))
+	if err != nil {
+		return err
+	}
+	c.bots[team] = bot
+	c.names[team] = name
+	return nil
+}
+
+// view gets what is shown to the team whose turn it is
+func (c *client) view() (*gameView, error) {
+	snapshot, err := c.game.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshot.Winners) == 0 {
+		if snapshot, err = c.game.GetSnapshot(snapshot.Turn); err != nil {
+			return nil, err
+		}
+	}
+	return newGameView(snapshot, c.names), nil
+}
+
+// show draws the board, the play tile, and the status of each team
+func (c *client) show() {
+	view, err := c.view()
+	if err != nil {
+		fmt.Fprintln(c.out, "error:", err)
+		return
+	}
+	fmt.Fprintln(c.out)
+	c.renderer.board(c.out, view)
+	fmt.Fprintln(c.out)
+	if view.PlayTile != nil && len(view.Winners) == 0 {
+		fmt.Fprintf(c.out, "%s's tile:\n", view.Turn)
+		c.renderer.tile(c.out, view.Teams, *view.PlayTile)
+		fmt.Fprintln(c.out)
+	}
+	c.renderer.status(c.out, view)
+}
+
+// playBots lets seated bots act until it is a person's turn or the game is over
+func (c *client) playBots() error {
+	for {
+		view, err := c.view()
+		if err != nil {
+			return err
+		}
+		bot, ok := c.bots[view.Turn]
+		if len(view.Winners) > 0 || !ok {
+			return nil
+		}
+		action, err := bot.Action(c.game, view.Turn)
+		if err != nil {
+			return err
+		}
+		if err := c.game.Do(action); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "%s (%s) %s\n", view.Turn, c.names[view.Turn], describe(action))
+		if action.ActionType == carcassonne.ActionPlaceToken {
+			c.show()
+		}
+	}
+}
+
+// describe gets a short description of an action
+func describe(action *bg.BoardGameAction) string {
+	switch details := action.MoreDetails.(type) {
+	case carcassonne.PlaceTileActionDetails:
+		return fmt.Sprintf("placed a tile at %d,%d", details.X, details.Y)
+	case carcassonne.PlaceTokenActionDetails:
+		if details.Pass {
+			return "passed"
+		}
+		return fmt.Sprintf("placed a %s on %s at %d,%d", details.Type, details.Side, details.X, details.Y)
+	}
+	return action.ActionType
+}
+
+// command runs a single line of input returning true when the player wants to quit
+func (c *client) command(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, nil
+	}
+	args := fields[1:]
+	switch strings.ToLower(fields[0]) {
+	case "quit", "exit", "q":
+		return true, nil
+	case "help", "h", "?":
+		fmt.Fprintf(c.out, help, strings.Join(carcassonne.BotNames(), ", "))
+		return false, nil
+	case "show", "s":
+		c.show()
+		return false, nil
+	case "moves", "m":
+		return false, c.moves()
+	case "rotate", "r":
+		actionType := carcassonne.ActionRotateTileRight
+		if len(args) > 0 && strings.HasPrefix(strings.ToLower(args[0]), "l") {
+			actionType = carcassonne.ActionRotateTileLeft
+		}
+		return false, c.do(&bg.BoardGameAction{ActionType: actionType})
+	case "place", "p":
+		if len(args) != 2 {
+			return false, fmt.Errorf("place requires X and Y")
+		}
+		x, err := strconv.Atoi(args[0])
+		if err != nil {
+			return false, err
+		}
+		y, err := strconv.Atoi(args[1])
+		if err != nil {
+			return false, err
+		}
+		return false, c.place(x, y)
+	case "token", "t":
+		if len(args) < 1 || len(args) > 2 {
+			return false, fmt.Errorf("token requires a side and an optional type")
+		}
+		typ := ""
+		if len(args) == 2 {
+			typ = args[1]
+		}
+		return false, c.token(args[0], typ)
+	case "pass":
+		return false, c.do(&bg.BoardGameAction{
+			ActionType:  carcassonne.ActionPlaceToken,
+			MoreDetails: carcassonne.PlaceTokenActionDetails{Pass: true},
+		})
+	case "bot":
+		if len(args) != 2 {
+			return false, fmt.Errorf("bot requires a team and a bot name")
+		}
+		return false, c.seat(args[0], args[1])
+	case "human":
+		if len(args) != 1 {
+			return false, fmt.Errorf("human requires a team")
+		}
+		delete(c.bots, args[0])
+		delete(c.names, args[0])
+		return false, nil
+	case "new":
+		if len(args) < 3 {
+			return false, fmt.Errorf("new requires a seed and at least two teams")
+		}
+		seed, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return false, err
+		}
+		if err := c.newGame(args[1:], seed); err != nil {
+			return false, err
+		}
+		c.show()
+		return false, nil
+	case "load":
+		if len(args) != 1 {
+			return false, fmt.Errorf("load requires a file")
+		}
+		if err := c.load(args[0]); err != nil {
+			return false, err
+		}
+		c.show()
+		return false, nil
+	case "save":
+		if len(args) != 1 {
+			return false, fmt.Errorf("save requires a file")
+		}
+		if err := c.save(args[0]); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(c.out, "saved to %s\n", args[0])
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown command %s, type help for a list of commands", fields[0])
+}
+
+// do performs an action for the team whose turn it is and redraws the game
+func (c *client) do(action *bg.BoardGameAction) error {
+	view, err := c.view()
+	if err != nil {
+		return err
+	}
+	action.Team = view.Turn
+	if err := c.game.Do(action); err != nil {
+		return err
+	}
+	c.show()
+	return nil
+}
+
+func (c *client) place(x, y int) error {
+	view, err := c.view()
+	if err != nil {
+		return err
+	}
+	if view.PlayTile == nil {
+		return fmt.Errorf("%s must place a token before placing a tile", view.Turn)
+	}
+	t := view.PlayTile
+	return c.do(&bg.BoardGameAction{
+		ActionType: carcassonne.ActionPlaceTile,
+		MoreDetails: carcassonne.PlaceTileActionDetails{
+			X: x,
+			Y: y,
+			Tile: carcassonne.TileActionDetails{
+				Top:                t.Sides[carcassonne.SideTop],
+				Right:              t.Sides[carcassonne.SideRight],
+				Bottom:             t.Sides[carcassonne.SideBottom],
+				Left:               t.Sides[carcassonne.SideLeft],
+				Center:             t.Center,
+				ConnectedCitySides: t.ConnectedCitySides,
+				Banner:             t.Banner,
+			},
+		},
+	})
+}
+
+// token places a token on the last placed tile inferring the token type from the side when not given
+func (c *client) token(side, typ string) error {
+	view, err := c.view()
+	if err != nil {
+		return err
+	}
+	if view.LastPlaced == nil || view.PlayTile != nil {
+		return fmt.Errorf("%s must place a tile before placing a token", view.Turn)
+	}
+	side = match(side, append(append([]string{"Center"}, carcassonne.Sides...), carcassonne.FarmSides...))
+	if side == "Center" {
+		side = ""
+		if typ == "" {
+			typ = carcassonne.Monk
+		}
+	}
+	if typ == "" {
+		switch {
+		case contains(carcassonne.FarmSides, side):
+			typ = carcassonne.Farmer
+		case contains(carcassonne.Sides, side):
+			typ = carcassonne.StructureTypeToTokenType[view.LastPlaced.Sides[side]]
+		}
+	}
+	return c.do(&bg.BoardGameAction{
+		ActionType: carcassonne.ActionPlaceToken,
+		MoreDetails: carcassonne.PlaceTokenActionDetails{
+			X:    view.LastPlaced.X,
+			Y:    view.LastPlaced.Y,
+			Type: match(typ, carcassonne.TokenTypes),
+			Side: side,
+		},
+	})
+}
+
+// moves lists every valid action for the team whose turn it is
+func (c *client) moves() error {
+	snapshot, err := c.game.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	targets, _ := snapshot.Targets.([]*bg.BoardGameAction)
+	lines := make([]string, 0, len(targets))
+	for _, target := range targets {
+		switch target.ActionType {
+		case carcassonne.ActionRotateTileLeft:
+			lines = append(lines, "rotate left")
+			continue
+		case carcassonne.ActionRotateTileRight:
+			lines = append(lines, "rotate right")
+			continue
+		}
+		switch details := target.MoreDetails.(type) {
+		case carcassonne.PlaceTileActionDetails:
+			lines = append(lines, fmt.Sprintf("place %d %d", details.X, details.Y))
+		case carcassonne.PlaceTokenActionDetails:
+			if details.Pass {
+				lines = append(lines, "pass")
+			} else if details.Type == carcassonne.Monk {
+				lines = append(lines, "token center")
+			} else {
+				lines = append(lines, fmt.Sprintf("token %s %s", details.Side, details.Type))
+			}
+		}
+	}
+	sort.Strings(lines)
+	fmt.Fprintln(c.out, strings.Join(lines, "\n"))
+	return nil
+}
+
+// match gets the option equal to s ignoring case or s itself when nothing matches
+func match(s string, options []string) string {
+	for _, option := range options {
+		if strings.EqualFold(s, option) {
+			return option
+		}
+	}
+	return s
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Client(t *testing.T) {
+	var out bytes.Buffer
+	c := newClient(&out, &renderer{glyphs: asciiGlyphs})
+	if err := c.newGame([]string{"Red", "Blue"}, 0); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	// the start tile has a city on top and a road running left to right
+	c.show()
+	assert.Contains(t, out.String(), "#######\n")
+	assert.Contains(t, out.String(), " 0  -------\n")
+
+	// the first tile only fits after rotating
+	_, err := c.command("place 0 1")
+	assert.Error(t, err)
+	for _, command := range []string{"rotate", "rotate left", "r right", "place 1 0", "token right"} {
+		if _, err := c.command(command); err != nil {
+			t.Error(command, err)
+			t.FailNow()
+		}
+	}
+	view, err := c.view()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, "Blue", view.Turn)
+	assert.Len(t, view.Tokens, 1)
+	assert.Equal(t, "Red", view.Tokens[0].Team)
+
+	// bots finish the game and the saved game loads back to the same game
+	if _, err := c.command("bot Blue random"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if _, err := c.command("bot Red greedy"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err := c.playBots(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	path := filepath.Join(t.TempDir(), "game.bgn")
+	if _, err := c.command("save " + path); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	saved := c.game.GetBGN()
+	if _, err := c.command("load " + path); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, saved.Actions, c.game.GetBGN().Actions)
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(out.String()), "wins") || strings.HasSuffix(strings.TrimSpace(out.String()), "tie"))
+
+	quit, _ := c.command("quit")
+	assert.True(t, quit)
+}
@@ -0,0 +1,72 @@
+// Command carcassonne plays Carcassonne in the terminal with any mix of people and bots
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	teams := flag.String("teams", "Red,Blue", "comma separated teams in turn order")
+	seed := flag.Int64("seed", 0, "seed used to shuffle the deck")
+	load := flag.String("load", "", "BGN file to load instead of starting a new game")
+	bots := flag.String("bots", "", "comma separated team=bot pairs to seat bots e.g. Blue=greedy")
+	ascii := flag.Bool("ascii", false, "draw tiles using only ASCII characters")
+	noColor := flag.Bool("no-color", false, "do not color teams")
+	flag.Parse()
+
+	r := &renderer{glyphs: unicodeGlyphs, color: !*noColor}
+	if *ascii {
+		r.glyphs = asciiGlyphs
+	}
+	c := newClient(os.Stdout, r)
+	var err error
+	if *load != "" {
+		err = c.load(*load)
+	} else {
+		err = c.newGame(strings.Split(*teams, ","), *seed)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *bots != "" {
+		for _, pair := range strings.Split(*bots, ",") {
+			team, bot, ok := strings.Cut(pair, "=")
+			if !ok {
+				log.Fatalf("invalid bot %s, expected team=bot", pair)
+			}
+			if err := c.seat(team, bot); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	c.run(os.Stdin)
+}
+
+// run reads and executes commands until the input ends or the player quits
+func (c *client) run(in io.Reader) {
+	scanner := bufio.NewScanner(in)
+	c.show()
+	for {
+		if err := c.playBots(); err != nil {
+			fmt.Fprintln(c.out, "error:", err)
+		}
+		fmt.Fprint(c.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(c.out)
+			return
+		}
+		quit, err := c.command(scanner.Text())
+		if err != nil {
+			fmt.Fprintln(c.out, "error:", err)
+		}
+		if quit {
+			return
+		}
+	}
+}
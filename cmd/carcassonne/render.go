@@ -0,0 +1,364 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	carcassonne "github.com/quibbble/go-carcassonne"
+)
+
+const (
+	// tileWidth and tileHeight are the number of characters used to draw a single tile
+	tileWidth  = 7
+	tileHeight = 5
+
+	reset = "\033[0m"
+)
+
+// teamColors are the ANSI colors used for each team in seat order
+var teamColors = []string{"\033[31m", "\033[34m", "\033[32m", "\033[33m", "\033[35m"}
+
+// glyphs are the characters used to draw the parts of a tile
+type glyphs struct {
+	city, farm, roadVertical, roadHorizontal, roadJunction, cloister, banner, empty rune
+}
+
+var (
+	asciiGlyphs   = glyphs{city: '#', farm: '.', roadVertical: '|', roadHorizontal: '-', roadJunction: '+', cloister: 'C', banner: '$', empty: ' '}
+	unicodeGlyphs = glyphs{city: '▒', farm: '·', roadVertical: '│', roadHorizontal: '─', roadJunction: '┼', cloister: '⌂', banner: '♦', empty: ' '}
+)
+
+// positions are the row and column within a tile where a side, farm side, or the center is drawn
+var positions = map[string][2]int{
+	carcassonne.SideTop: {1, 3}, carcassonne.SideRight: {2, 5}, carcassonne.SideBottom: {3, 3}, carcassonne.SideLeft: {2, 1},
+	carcassonne.FarmSideTopA: {1, 2}, carcassonne.FarmSideTopB: {1, 4}, carcassonne.FarmSideRightA: {1, 5}, carcassonne.FarmSideRightB: {3, 5},
+	carcassonne.FarmSideBottomA: {3, 4}, carcassonne.FarmSideBottomB: {3, 2}, carcassonne.FarmSideLeftA: {3, 1}, carcassonne.FarmSideLeftB: {1, 1},
+	carcassonne.Cloister: {2, 3},
+}
+
+// cell is a single drawn character with an optional color
+type cell struct {
+	r     rune
+	color string
+}
+
+// renderer draws games to a terminal
+type renderer struct {
+	glyphs glyphs
+	color  bool
+}
+
+func (r *renderer) colorOf(teams []string, team string) string {
+	if !r.color {
+		return ""
+	}
+	for i, t := range teams {
+		if t == team {
+			return teamColors[i%len(teamColors)]
+		}
+	}
+	return ""
+}
+
+// tokenRune is the character drawn for a token which is the first letter of its type
+func tokenRune(typ string) rune {
+	return rune(typ[0])
+}
+
+// drawTile draws the sides, center, and tokens of a tile into a grid of cells
+func (r *renderer) drawTile(teams []string, t tileView, tokens []tokenView) [tileHeight][tileWidth]cell {
+	var grid [tileHeight][tileWidth]cell
+	for row := range grid {
+		for col := range grid[row] {
+			grid[row][col] = cell{r: r.glyphs.farm}
+		}
+	}
+	set := func(row, col int, ch rune, color string) {
+		grid[row][col] = cell{r: ch, color: color}
+	}
+	edge := func(side string) (ch rune, color string) {
+		if len(t.Teams[side]) > 0 {
+			color = r.colorOf(teams, t.Teams[side][0])
+		}
+		switch t.Sides[side] {
+		case carcassonne.City:
+			return r.glyphs.city, color
+		case carcassonne.Road:
+			if side == carcassonne.SideTop || side == carcassonne.SideBottom {
+				return r.glyphs.roadVertical, color
+			}
+			return r.glyphs.roadHorizontal, color
+		}
+		return r.glyphs.farm, ""
+	}
+	cities, roads := 0, 0
+	for _, side := range carcassonne.Sides {
+		switch t.Sides[side] {
+		case carcassonne.City:
+			cities++
+		case carcassonne.Road:
+			roads++
+		}
+	}
+	// cities fill the whole edge and the row or column next to it
+	for _, side := range carcassonne.Sides {
+		if t.Sides[side] != carcassonne.City {
+			continue
+		}
+		ch, color := edge(side)
+		switch side {
+		case carcassonne.SideTop, carcassonne.SideBottom:
+			row, inner := 0, 1
+			if side == carcassonne.SideBottom {
+				row, inner = tileHeight-1, tileHeight-2
+			}
+			for col := 0; col < tileWidth; col++ {
+				set(row, col, ch, color)
+			}
+			for col := 2; col < tileWidth-2; col++ {
+				set(inner, col, ch, color)
+			}
+		default:
+			col, inner := 0, 1
+			if side == carcassonne.SideRight {
+				col, inner = tileWidth-1, tileWidth-2
+			}
+			for row := 0; row < tileHeight; row++ {
+				set(row, col, ch, color)
+			}
+			set(2, inner, ch, color)
+		}
+	}
+	if t.ConnectedCitySides && cities > 1 {
+		ch, color := edge(firstSide(t, carcassonne.City))
+		for col := 2; col < tileWidth-2; col++ {
+			set(2, col, ch, color)
+		}
+	}
+	// roads run from the edge to the center of the tile
+	for _, side := range carcassonne.Sides {
+		if t.Sides[side] != carcassonne.Road {
+			continue
+		}
+		ch, color := edge(side)
+		switch side {
+		case carcassonne.SideTop:
+			set(0, 3, ch, color)
+			set(1, 3, ch, color)
+		case carcassonne.SideBottom:
+			set(3, 3, ch, color)
+			set(4, 3, ch, color)
+		case carcassonne.SideLeft:
+			set(2, 0, ch, color)
+			set(2, 1, ch, color)
+			set(2, 2, ch, color)
+		case carcassonne.SideRight:
+			set(2, 4, ch, color)
+			set(2, 5, ch, color)
+			set(2, 6, ch, color)
+		}
+	}
+	switch {
+	case t.Center == carcassonne.Cloister:
+		color := ""
+		if t.CenterTeam != "" {
+			color = r.colorOf(teams, t.CenterTeam)
+		}
+		set(2, 3, r.glyphs.cloister, color)
+	case roads == 2 && t.Sides[carcassonne.SideTop] == carcassonne.Road && t.Sides[carcassonne.SideBottom] == carcassonne.Road:
+		ch, color := edge(carcassonne.SideTop)
+		set(2, 3, ch, color)
+	case roads == 2 && t.Sides[carcassonne.SideLeft] == carcassonne.Road && t.Sides[carcassonne.SideRight] == carcassonne.Road:
+		ch, color := edge(carcassonne.SideLeft)
+		set(2, 3, ch, color)
+	case roads > 0:
+		set(2, 3, r.glyphs.roadJunction, "")
+	}
+	if t.Banner {
+		row, col := 2, 3
+		switch firstSide(t, carcassonne.City) {
+		case carcassonne.SideTop:
+			row, col = 1, 2
+		case carcassonne.SideBottom:
+			row, col = 3, 2
+		case carcassonne.SideLeft:
+			row, col = 1, 0
+		case carcassonne.SideRight:
+			row, col = 1, 6
+		}
+		set(row, col, r.glyphs.banner, "")
+	}
+	// farms won at the end of the game are colored by the winning team
+	for farmSide, winners := range t.FarmTeams {
+		if len(winners) == 0 {
+			continue
+		}
+		position := positions[farmSide]
+		if grid[position[0]][position[1]].r == r.glyphs.farm {
+			set(position[0], position[1], r.glyphs.farm, r.colorOf(teams, winners[0]))
+		}
+	}
+	for _, token := range tokens {
+		position, ok := positions[token.Side]
+		if token.Type == carcassonne.Monk {
+			position, ok = positions[carcassonne.Cloister], true
+		}
+		if !ok {
+			continue
+		}
+		set(position[0], position[1], tokenRune(token.Type), r.colorOf(teams, token.Team))
+	}
+	return grid
+}
+
+// firstSide gets the first side of the tile with the given structure
+func firstSide(t tileView, structure string) string {
+	for _, side := range carcassonne.Sides {
+		if t.Sides[side] == structure {
+			return side
+		}
+	}
+	return ""
+}
+
+// writeGrid writes rows of cells adding color codes when needed
+func writeGrid(w io.Writer, rows [][]cell) {
+	var b strings.Builder
+	for _, row := range rows {
+		color := ""
+		for _, c := range row {
+			if c.color != color {
+				if color != "" {
+					b.WriteString(reset)
+				}
+				b.WriteString(c.color)
+				color = c.color
+			}
+			b.WriteRune(c.r)
+		}
+		if color != "" {
+			b.WriteString(reset)
+		}
+		b.WriteByte('\n')
+	}
+	fmt.Fprint(w, b.String())
+}
+
+// board draws every placed tile and labels the spaces where the play tile may be placed
+func (r *renderer) board(w io.Writer, view *gameView) {
+	if len(view.Board) == 0 {
+		return
+	}
+	minX, maxX, minY, maxY := view.Board[0].X, view.Board[0].X, view.Board[0].Y, view.Board[0].Y
+	grow := func(x, y int) {
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+	for _, t := range view.Board {
+		grow(t.X, t.Y)
+	}
+	for _, space := range view.Spaces {
+		grow(space[0], space[1])
+	}
+	placed := make(map[[2]int]tileView)
+	for _, t := range view.Board {
+		placed[[2]int{t.X, t.Y}] = t
+	}
+	tokens := make(map[[2]int][]tokenView)
+	for _, token := range view.Tokens {
+		tokens[[2]int{token.X, token.Y}] = append(tokens[[2]int{token.X, token.Y}], token)
+	}
+	spaces := make(map[[2]int]bool)
+	for _, space := range view.Spaces {
+		spaces[space] = true
+	}
+	label := func(s string, width int) string {
+		padding := width - len(s)
+		if padding < 0 {
+			return s[:width]
+		}
+		return strings.Repeat(" ", padding/2) + s + strings.Repeat(" ", padding-padding/2)
+	}
+	margin := 4
+	header := make([]cell, 0)
+	for _, ch := range strings.Repeat(" ", margin) {
+		header = append(header, cell{r: ch})
+	}
+	for x := minX; x <= maxX; x++ {
+		for _, ch := range label(fmt.Sprintf("%d", x), tileWidth) {
+			header = append(header, cell{r: ch})
+		}
+	}
+	rows := [][]cell{header}
+	// +Y is up so the highest row is drawn first
+	for y := maxY; y >= minY; y-- {
+		lines := make([][]cell, tileHeight)
+		for row := range lines {
+			prefix := strings.Repeat(" ", margin)
+			if row == tileHeight/2 {
+				prefix = label(fmt.Sprintf("%d", y), margin-1) + " "
+			}
+			for _, ch := range prefix {
+				lines[row] = append(lines[row], cell{r: ch})
+			}
+		}
+		for x := minX; x <= maxX; x++ {
+			location := [2]int{x, y}
+			if t, ok := placed[location]; ok {
+				grid := r.drawTile(view.Teams, t, tokens[location])
+				for row := range grid {
+					lines[row] = append(lines[row], grid[row][:]...)
+				}
+				continue
+			}
+			for row := range lines {
+				text := strings.Repeat(string(r.glyphs.empty), tileWidth)
+				if spaces[location] {
+					switch row {
+					case 0, tileHeight - 1:
+						text = " " + strings.Repeat("-", tileWidth-2) + " "
+					case tileHeight / 2:
+						text = label(fmt.Sprintf("%d,%d", x, y), tileWidth)
+					}
+				}
+				for _, ch := range text {
+					lines[row] = append(lines[row], cell{r: ch})
+				}
+			}
+		}
+		rows = append(rows, lines...)
+	}
+	writeGrid(w, rows)
+}
+
+// tile draws a single tile on its own such as the current play tile
+func (r *renderer) tile(w io.Writer, teams []string, t tileView) {
+	grid := r.drawTile(teams, t, nil)
+	rows := make([][]cell, 0, tileHeight)
+	for row := range grid {
+		rows = append(rows, append([]cell{{r: ' '}, {r: ' '}, {r: ' '}, {r: ' '}}, grid[row][:]...))
+	}
+	writeGrid(w, rows)
+}
+
+// status writes the scores, remaining tokens, and whose turn it is
+func (r *renderer) status(w io.Writer, view *gameView) {
+	for _, team := range view.Teams {
+		marker := " "
+		if team == view.Turn && len(view.Winners) == 0 {
+			marker = "*"
+		}
+		name := team
+		if color := r.colorOf(view.Teams, team); color != "" {
+			name = color + team + reset
+		}
+		fmt.Fprintf(w, "%s %s  score %d  tokens %d", marker, name, view.Scores[team], view.TokensLeft[team])
+		if bot := view.Bots[team]; bot != "" {
+			fmt.Fprintf(w, "  (%s bot)", bot)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d tiles remaining\n", view.TilesRemaining)
+	fmt.Fprintln(w, view.Message)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	bg "github.com/quibbble/go-boardgame"
+	carcassonne "github.com/quibbble/go-carcassonne"
+)
+
+// tileView is the part of a tile needed to draw it
+type tileView struct {
+	X, Y               int
+	Sides              map[string]string
+	Center             string
+	ConnectedCitySides bool
+	Banner             bool
+	Teams              map[string][]string
+	FarmTeams          map[string][]string
+	CenterTeam         string
+}
+
+// tokenView is the part of a token needed to draw it
+type tokenView struct {
+	X, Y             int
+	Team, Type, Side string
+}
+
+// gameView is everything drawn for a single turn of the game
+type gameView struct {
+	Turn           string
+	Teams          []string
+	Winners        []string
+	Message        string
+	Board          []tileView
+	Tokens         []tokenView
+	Scores         map[string]int
+	TokensLeft     map[string]int
+	TilesRemaining int
+	PlayTile       *tileView
+	LastPlaced     *tileView
+	Spaces         [][2]int
+	Bots           map[string]string
+}
+
+// newGameView builds a view from the snapshot of the team whose turn it is
+func newGameView(snapshot *bg.BoardGameSnapshot, bots map[string]string) *gameView {
+	data := snapshot.MoreData.(carcassonne.CarcassonneSnapshotData)
+	view := &gameView{
+		Turn:           snapshot.Turn,
+		Teams:          snapshot.Teams,
+		Winners:        snapshot.Winners,
+		Message:        snapshot.Message,
+		Scores:         data.Scores,
+		TokensLeft:     data.Tokens,
+		TilesRemaining: data.TilesRemaining,
+		Bots:           bots,
+	}
+	for _, t := range data.Board {
+		view.Board = append(view.Board, tileView{
+			X: t.X, Y: t.Y, Sides: t.Sides, Center: t.Center, ConnectedCitySides: t.ConnectedCitySides, Banner: t.Banner,
+			Teams: t.Teams, FarmTeams: t.FarmTeams, CenterTeam: t.CenterTeam,
+		})
+	}
+	for _, token := range data.BoardTokens {
+		view.Tokens = append(view.Tokens, tokenView{X: token.X, Y: token.Y, Team: token.Team, Type: token.Type, Side: token.Side})
+	}
+	if t := data.PlayTile; t != nil {
+		view.PlayTile = &tileView{X: t.X, Y: t.Y, Sides: t.Sides, Center: t.Center, ConnectedCitySides: t.ConnectedCitySides, Banner: t.Banner}
+	}
+	if t := data.LastPlacedTiles[snapshot.Turn]; t != nil {
+		view.LastPlaced = &tileView{X: t.X, Y: t.Y, Sides: t.Sides, Center: t.Center, ConnectedCitySides: t.ConnectedCitySides, Banner: t.Banner}
+	}
+	targets, _ := snapshot.Targets.([]*bg.BoardGameAction)
+	for _, target := range targets {
+		if target.ActionType != carcassonne.ActionPlaceTile {
+			continue
+		}
+		details := target.MoreDetails.(carcassonne.PlaceTileActionDetails)
+		view.Spaces = append(view.Spaces, [2]int{details.X, details.Y})
+	}
+	return view
+}
@@ -7,24 +7,58 @@ import (
 
 type deck struct {
 	tiles  []*tile
+	source *source
 	random *rand.Rand
+
+	// ordered is the number of tiles at the top of the deck drawn in a fixed order which are never shuffled
+	ordered int
 }
 
-func newDeck(random *rand.Rand) *deck {
+// newDeck creates a shuffled deck where the tile types in order, if any, are drawn first
+func newDeck(seed int64, order []int) *deck {
+	amounts := make([]int, len(tiles))
+	for typ, tileAmount := range tiles {
+		amounts[typ] = tileAmount.amount
+	}
+	for _, typ := range order {
+		amounts[typ]--
+	}
 	d := make([]*tile, 0)
-	for _, tileAmount := range tiles {
-		for i := 0; i < tileAmount.amount; i++ {
+	for typ, tileAmount := range tiles {
+		for i := 0; i < amounts[typ]; i++ {
 			d = append(d, tileAmount.tile.copy())
 		}
 	}
+	source := newSource(seed)
 	result := &deck{
-		tiles:  d,
-		random: random,
+		tiles:   d,
+		source:  source,
+		random:  rand.New(source),
+		ordered: len(order),
 	}
 	result.Shuffle()
+	// tiles are drawn from the end of the deck
+	for i := len(order) - 1; i >= 0; i-- {
+		result.tiles = append(result.tiles, tiles[order[i]].tile.copy())
+	}
 	return result
 }
 
+// validateDeckOrder checks that order only uses tile types that are in the deck
+func validateDeckOrder(order []int) error {
+	amounts := make([]int, len(tiles))
+	for _, typ := range order {
+		if typ < 0 || typ >= len(tiles) {
+			return fmt.Errorf("got %d but wanted a tile type from 0 to %d", typ, len(tiles)-1)
+		}
+		amounts[typ]++
+		if amounts[typ] > tiles[typ].amount {
+			return fmt.Errorf("tile type %d is used more than the %d times it is in the deck", typ, tiles[typ].amount)
+		}
+	}
+	return nil
+}
+
 func (d *deck) Shuffle() {
 	for i := 0; i < len(d.tiles); i++ {
 		r := d.random.Intn(len(d.tiles))
@@ -38,21 +72,120 @@ func (d *deck) Empty() bool {
 	return len(d.tiles) == 0
 }
 
-func (d *deck) Add(tiles ...*tile) {
-	d.tiles = append(d.tiles, tiles...)
+// Add shuffles tiles into the deck below the top keep tiles and the tiles drawn in a fixed order
+func (d *deck) Add(keep int, tiles ...*tile) {
+	split := max(len(d.tiles)-max(keep, d.ordered), 0)
+	top := append(make([]*tile, 0, len(d.tiles)-split), d.tiles[split:]...)
+	d.tiles = append(d.tiles[:split], tiles...)
 	d.Shuffle()
+	d.tiles = append(d.tiles, top...)
 }
 
+// PutBack returns a drawn tile to the top of the deck so it is drawn next
+func (d *deck) PutBack(t *tile) {
+	d.tiles = append(d.tiles, t)
+	d.ordered++
+}
+
+// Draw removes the top tile of the deck and returns a copy of it
+// tiles in the deck are never modified so that they can be shared between clones
 func (d *deck) Draw() (*tile, error) {
 	size := len(d.tiles)
 	if size <= 0 {
 		return nil, fmt.Errorf("cannot draw from empty deck")
 	}
-	tile := d.tiles[size-1]
+	tile := d.tiles[size-1].copy()
 	d.tiles = d.tiles[:size-1]
+	if d.ordered > 0 {
+		d.ordered--
+	}
 	return tile, nil
 }
 
 func (d *deck) Size() int {
 	return len(d.tiles)
 }
+
+func (d *deck) clone() *deck {
+	source := d.source.clone()
+	return &deck{
+		tiles:   append(make([]*tile, 0, len(d.tiles)), d.tiles...),
+		source:  source,
+		random:  rand.New(source),
+		ordered: d.ordered,
+	}
+}
+
+// rngLen and rngTap are the lag and tap of the additive lagged fibonacci generator behind rand.NewSource
+const (
+	rngLen = 607
+	rngTap = 273
+)
+
+// source is a rand.Source producing the same values as rand.NewSource whose state can be copied when cloning
+type source struct {
+	seed  int64
+	calls uint64
+	first *[rngLen]uint64 // first values of the seed, lazily created as seeding is expensive and clones rarely need randomness
+	vec   *[rngLen]int64  // generator state once the first values are used up
+	tap   int
+	feed  int
+}
+
+func newSource(seed int64) *source {
+	return &source{
+		seed: seed,
+	}
+}
+
+func (s *source) Int63() int64 {
+	return int64(s.Uint64() & (1<<63 - 1))
+}
+
+func (s *source) Uint64() uint64 {
+	if s.calls < rngLen {
+		if s.first == nil {
+			s.first = new([rngLen]uint64)
+			src := rand.NewSource(s.seed).(rand.Source64)
+			for i := range s.first {
+				s.first[i] = src.Uint64()
+			}
+		}
+		s.calls++
+		return s.first[s.calls-1]
+	}
+	if s.vec == nil {
+		// the first values overwrote the whole seeded state and moved tap and feed back to where seeding left them
+		s.vec = new([rngLen]int64)
+		for i, v := range s.first {
+			s.vec[(2*rngLen-rngTap-1-i)%rngLen] = int64(v)
+		}
+		s.tap, s.feed = 0, rngLen-rngTap
+	}
+	s.tap--
+	if s.tap < 0 {
+		s.tap += rngLen
+	}
+	s.feed--
+	if s.feed < 0 {
+		s.feed += rngLen
+	}
+	x := s.vec[s.feed] + s.vec[s.tap]
+	s.vec[s.feed] = x
+	s.calls++
+	return uint64(x)
+}
+
+func (s *source) Seed(seed int64) {
+	*s = source{seed: seed}
+}
+
+func (s *source) clone() *source {
+	c := *s
+	// the first values never change so are shared while the generator state is copied
+	if s.vec != nil {
+		vec := *s.vec
+		c.vec = &vec
+	}
+	return &c
+}
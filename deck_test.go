@@ -0,0 +1,38 @@
+package go_carcassonne
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Source(t *testing.T) {
+	for _, seed := range []int64{0, 1, 42, -7} {
+		expected := rand.NewSource(seed).(rand.Source64)
+		src := newSource(seed)
+		clones := make(map[int]*source)
+		for i := 0; i < 3*rngLen; i++ {
+			if i%250 == 0 || i == rngLen-1 || i == rngLen || i == rngLen+1 {
+				clones[i] = src.clone()
+			}
+			if i%3 == 0 {
+				assert.Equal(t, expected.Int63(), src.Int63())
+			} else {
+				assert.Equal(t, expected.Uint64(), src.Uint64())
+			}
+		}
+		// clones continue from where they were cloned however far the source has drawn since
+		for at, clone := range clones {
+			expected := rand.NewSource(seed).(rand.Source64)
+			for i := 0; i < at; i++ {
+				expected.Uint64()
+			}
+			for i := 0; i < 2*rngLen; i++ {
+				assert.Equal(t, expected.Uint64(), clone.Uint64())
+			}
+		}
+		src.Seed(seed)
+		assert.Equal(t, rand.NewSource(seed).Int63(), src.Int63())
+	}
+}
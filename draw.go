@@ -0,0 +1,309 @@
+package go_carcassonne
+
+import (
+	"image/color"
+)
+
+// drawTileSize is the width and height of a tile in a drawing
+const drawTileSize = 64
+
+var (
+	farmColor     = color.RGBA{R: 0x9c, G: 0xc4, B: 0x5a, A: 0xff}
+	cityColor     = color.RGBA{R: 0xc8, G: 0x8e, B: 0x4e, A: 0xff}
+	roadColor     = color.RGBA{R: 0xf2, G: 0xee, B: 0xe0, A: 0xff}
+	cloisterColor = color.RGBA{R: 0xa8, G: 0x3c, B: 0x32, A: 0xff}
+	bannerColor   = color.RGBA{R: 0x2a, G: 0x4d, B: 0x9c, A: 0xff}
+	gridColor     = color.RGBA{R: 0x5a, G: 0x6e, B: 0x3c, A: 0xff}
+	outlineColor  = color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff}
+	backColor     = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+// TeamColors are the colors used to draw each team in turn order
+var TeamColors = []color.RGBA{
+	{R: 0xd6, G: 0x27, B: 0x28, A: 0xff},
+	{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff},
+	{R: 0xf2, G: 0xd0, B: 0x24, A: 0xff},
+	{R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff},
+	{R: 0x94, G: 0x67, B: 0xbd, A: 0xff},
+}
+
+// point is a location in a drawing where +Y is down
+type point struct {
+	X, Y float64
+}
+
+// shape is a filled polygon or circle, shapes are drawn in order so later shapes cover earlier ones
+type shape struct {
+	points []point
+	center point
+	radius float64
+	fill   color.RGBA
+}
+
+// drawing is a list of shapes that can be output to any image format
+type drawing struct {
+	width, height float64
+	shapes        []*shape
+}
+
+func (d *drawing) polygon(fill color.RGBA, points ...point) {
+	d.shapes = append(d.shapes, &shape{points: points, fill: fill})
+}
+
+func (d *drawing) circle(fill color.RGBA, center point, radius float64) {
+	d.shapes = append(d.shapes, &shape{center: center, radius: radius, fill: fill})
+}
+
+func (d *drawing) rect(fill color.RGBA, x, y, w, h float64) {
+	d.polygon(fill, point{x, y}, point{x + w, y}, point{x + w, y + h}, point{x, y + h})
+}
+
+// teamColor gets the color of team based on its position in teams
+func teamColor(teams []string, team string) color.RGBA {
+	index := indexOf(teams, team)
+	if index < 0 {
+		return outlineColor
+	}
+	return TeamColors[index%len(TeamColors)]
+}
+
+// mix blends two colors evenly
+func mix(c1, c2 color.RGBA) color.RGBA {
+	return color.RGBA{
+		R: uint8((int(c1.R) + int(c2.R)) / 2),
+		G: uint8((int(c1.G) + int(c2.G)) / 2),
+		B: uint8((int(c1.B) + int(c2.B)) / 2),
+		A: 0xff,
+	}
+}
+
+// structureColor gets the color of a structure which is the color of the first team that won it if any
+func structureColor(teams []string, base color.RGBA, winners []string) color.RGBA {
+	if len(winners) == 0 {
+		return base
+	}
+	return teamColor(teams, winners[0])
+}
+
+// sideCorners are the corners of a tile on either end of a side in clockwise order, in fractions of a tile
+var sideCorners = map[string][2]point{
+	SideTop:    {{0, 0}, {1, 0}},
+	SideRight:  {{1, 0}, {1, 1}},
+	SideBottom: {{1, 1}, {0, 1}},
+	SideLeft:   {{0, 1}, {0, 0}},
+}
+
+// farmSideCorners are the corner and side middle of a tile bounding each farm side, in fractions of a tile
+var farmSideCorners = map[string][2]point{
+	FarmSideTopA:    {{0, 0}, {0.5, 0}},
+	FarmSideTopB:    {{0.5, 0}, {1, 0}},
+	FarmSideRightA:  {{1, 0}, {1, 0.5}},
+	FarmSideRightB:  {{1, 0.5}, {1, 1}},
+	FarmSideBottomA: {{1, 1}, {0.5, 1}},
+	FarmSideBottomB: {{0.5, 1}, {0, 1}},
+	FarmSideLeftA:   {{0, 1}, {0, 0.5}},
+	FarmSideLeftB:   {{0, 0.5}, {0, 0}},
+}
+
+// tokenPositions are where tokens are drawn on a tile for each side and farm side, in fractions of a tile
+var tokenPositions = map[string]point{
+	SideTop: {0.5, 0.18}, SideRight: {0.82, 0.5}, SideBottom: {0.5, 0.82}, SideLeft: {0.18, 0.5},
+	FarmSideTopA: {0.2, 0.08}, FarmSideTopB: {0.8, 0.08}, FarmSideRightA: {0.92, 0.2}, FarmSideRightB: {0.92, 0.8},
+	FarmSideBottomA: {0.8, 0.92}, FarmSideBottomB: {0.2, 0.92}, FarmSideLeftA: {0.08, 0.8}, FarmSideLeftB: {0.08, 0.2},
+	Cloister: {0.5, 0.5},
+}
+
+// bounds are the smallest and largest locations on a board
+type bounds struct {
+	minX, maxX, minY, maxY int
+}
+
+func boardBounds(board []*tile) bounds {
+	b := bounds{board[0].X, board[0].X, board[0].Y, board[0].Y}
+	for _, t := range board {
+		b.minX, b.maxX = min(b.minX, t.X), max(b.maxX, t.X)
+		b.minY, b.maxY = min(b.minY, t.Y), max(b.maxY, t.Y)
+	}
+	return b
+}
+
+// newDrawing draws the board and tokens where tile colors come from the teams that won each structure
+func newDrawing(teams []string, board []*tile, tokens []*token) *drawing {
+	if len(board) == 0 {
+		return &drawing{}
+	}
+	return newDrawingWithin(teams, board, tokens, boardBounds(board))
+}
+
+// newDrawingWithin draws the board and tokens sized to fit b
+func newDrawingWithin(teams []string, board []*tile, tokens []*token, b bounds) *drawing {
+	d := &drawing{
+		width:  float64((b.maxX - b.minX + 1) * drawTileSize),
+		height: float64((b.maxY - b.minY + 1) * drawTileSize),
+	}
+	d.rect(backColor, 0, 0, d.width, d.height)
+	// +Y is up on the board but down in a drawing
+	origin := func(x, y int) point {
+		return point{float64((x - b.minX) * drawTileSize), float64((b.maxY - y) * drawTileSize)}
+	}
+	for _, t := range board {
+		d.tile(teams, t, origin(t.X, t.Y))
+	}
+	for _, token := range tokens {
+		d.token(teams, token, origin(token.X, token.Y))
+	}
+	return d
+}
+
+// tile draws the farms, cities, roads, cloister, and banner of a tile with its top left corner at o
+func (d *drawing) tile(teams []string, t *tile, o point) {
+	at := func(p point) point {
+		return point{o.X + p.X*drawTileSize, o.Y + p.Y*drawTileSize}
+	}
+	center := at(point{0.5, 0.5})
+	// farms are split into eight triangles meeting at the center, farms won at the end of the game are a pale team color
+	for _, farmSide := range FarmSides {
+		corners := farmSideCorners[farmSide]
+		fill := farmColor
+		if winners := t.FarmTeams[farmSide]; len(winners) > 0 {
+			fill = mix(backColor, teamColor(teams, winners[0]))
+		}
+		d.polygon(fill, at(corners[0]), at(corners[1]), center)
+	}
+	roads := make([]string, 0)
+	cities := make([]string, 0)
+	for _, side := range Sides {
+		switch t.Sides[side] {
+		case Road:
+			roads = append(roads, side)
+		case City:
+			cities = append(cities, side)
+		}
+	}
+	if len(cities) > 0 {
+		if t.ConnectedCitySides && len(cities) > 1 {
+			// connected cities cover the tile leaving space for the sides that are not city
+			fill := structureColor(teams, cityColor, t.Teams[cities[0]])
+			d.polygon(fill, cityOutline(cities, at)...)
+		} else {
+			// separate cities are shallow segments along each side
+			for _, side := range cities {
+				fill := structureColor(teams, cityColor, t.Teams[side])
+				corners := sideCorners[side]
+				inner := func(p point) point {
+					return point{p.X + (0.5-p.X)*0.45, p.Y + (0.5-p.Y)*0.45}
+				}
+				d.polygon(fill, at(corners[0]), at(corners[1]), at(inner(corners[1])), at(inner(corners[0])))
+			}
+		}
+	}
+	// roads run from the middle of a side to the center
+	width := 0.12
+	for _, side := range roads {
+		fill := structureColor(teams, roadColor, t.Teams[side])
+		switch side {
+		case SideTop:
+			d.polygon(fill, at(point{0.5 - width/2, 0}), at(point{0.5 + width/2, 0}), at(point{0.5 + width/2, 0.5}), at(point{0.5 - width/2, 0.5}))
+		case SideBottom:
+			d.polygon(fill, at(point{0.5 - width/2, 0.5}), at(point{0.5 + width/2, 0.5}), at(point{0.5 + width/2, 1}), at(point{0.5 - width/2, 1}))
+		case SideLeft:
+			d.polygon(fill, at(point{0, 0.5 - width/2}), at(point{0.5, 0.5 - width/2}), at(point{0.5, 0.5 + width/2}), at(point{0, 0.5 + width/2}))
+		case SideRight:
+			d.polygon(fill, at(point{0.5, 0.5 - width/2}), at(point{1, 0.5 - width/2}), at(point{1, 0.5 + width/2}), at(point{0.5, 0.5 + width/2}))
+		}
+	}
+	// roads that end on the tile end at a village
+	if len(roads) == 1 && t.Center != Cloister || len(roads) > 2 {
+		d.rect(outlineColor, center.X-0.1*drawTileSize, center.Y-0.1*drawTileSize, 0.2*drawTileSize, 0.2*drawTileSize)
+	}
+	if t.Banner && len(cities) > 0 {
+		// banners are shields drawn inside the first city side
+		p := tokenPositions[cities[0]]
+		p = point{p.X + (0.5-p.X)*0.5 - 0.15, p.Y + (0.5-p.Y)*0.5 - 0.12}
+		if p.X < 0.05 {
+			p.X = 0.05
+		}
+		d.polygon(backColor, at(point{p.X, p.Y}), at(point{p.X + 0.14, p.Y}), at(point{p.X + 0.14, p.Y + 0.1}), at(point{p.X + 0.07, p.Y + 0.16}), at(point{p.X, p.Y + 0.1}))
+		d.polygon(bannerColor, at(point{p.X + 0.02, p.Y + 0.02}), at(point{p.X + 0.12, p.Y + 0.02}), at(point{p.X + 0.12, p.Y + 0.09}), at(point{p.X + 0.07, p.Y + 0.13}), at(point{p.X + 0.02, p.Y + 0.09}))
+	}
+	if t.Center == Cloister {
+		fill := cloisterColor
+		if t.CenterTeam != "" {
+			fill = teamColor(teams, t.CenterTeam)
+		}
+		d.polygon(outlineColor, at(point{0.3, 0.38}), at(point{0.5, 0.22}), at(point{0.7, 0.38}), at(point{0.7, 0.72}), at(point{0.3, 0.72}))
+		d.polygon(fill, at(point{0.33, 0.39}), at(point{0.5, 0.26}), at(point{0.67, 0.39}), at(point{0.67, 0.69}), at(point{0.33, 0.69}))
+	}
+	// tile edges are drawn last so neighbouring tiles are easy to tell apart
+	d.rect(gridColor, o.X, o.Y, drawTileSize, 1)
+	d.rect(gridColor, o.X, o.Y, 1, drawTileSize)
+	d.rect(gridColor, o.X, o.Y+drawTileSize-1, drawTileSize, 1)
+	d.rect(gridColor, o.X+drawTileSize-1, o.Y, 1, drawTileSize)
+}
+
+// cityOutline gets the outline of a city connecting two or more sides of a tile
+func cityOutline(cities []string, at func(point) point) []point {
+	outline := make([]point, 0)
+	switch len(cities) {
+	case 2:
+		first, second := sideCorners[cities[0]], sideCorners[cities[1]]
+		if AcrossSide[cities[0]] == cities[1] {
+			// a band between opposite sides narrowing towards the center
+			waist := func(side string) point {
+				corners := sideCorners[side]
+				return point{(corners[0].X+corners[1].X)/4 + 0.25, (corners[0].Y+corners[1].Y)/4 + 0.25}
+			}
+			outline = append(outline, first[0], first[1], waist(ClockwiseSide[cities[0]]), second[0], second[1], waist(ClockwiseSide[cities[1]]))
+		} else {
+			// half the tile cut along the diagonal between adjacent sides
+			if ClockwiseSide[cities[1]] == cities[0] {
+				first, second = second, first
+			}
+			outline = append(outline, first[0], first[1], second[1])
+		}
+	case 3:
+		// the whole tile with a notch cut into the side that is not city
+		for _, side := range Sides {
+			corners := sideCorners[side]
+			if contains(cities, side) {
+				outline = append(outline, corners[0], corners[1])
+				continue
+			}
+			inner := func(p point) point {
+				return point{p.X + (0.5-p.X)*0.5, p.Y + (0.5-p.Y)*0.5}
+			}
+			outline = append(outline, corners[0], inner(corners[0]), inner(corners[1]), corners[1])
+		}
+	default:
+		for _, side := range Sides {
+			outline = append(outline, sideCorners[side][0])
+		}
+	}
+	for i := range outline {
+		outline[i] = at(outline[i])
+	}
+	return outline
+}
+
+// token draws a token in its team color on the tile with its top left corner at o
+// farmers lie down so they are drawn as diamonds while all other tokens are drawn as circles
+func (d *drawing) token(teams []string, token *token, o point) {
+	p, ok := tokenPositions[token.Side]
+	if token.Type == Monk {
+		p, ok = tokenPositions[Cloister], true
+	}
+	if !ok {
+		return
+	}
+	c := point{o.X + p.X*drawTileSize, o.Y + p.Y*drawTileSize}
+	fill := teamColor(teams, token.Team)
+	if token.Type == Farmer {
+		r := 0.1 * drawTileSize
+		d.polygon(outlineColor, point{c.X, c.Y - r - 1.5}, point{c.X + r + 1.5, c.Y}, point{c.X, c.Y + r + 1.5}, point{c.X - r - 1.5, c.Y})
+		d.polygon(fill, point{c.X, c.Y - r}, point{c.X + r, c.Y}, point{c.X, c.Y + r}, point{c.X - r, c.Y})
+		return
+	}
+	r := 0.09 * drawTileSize
+	d.circle(outlineColor, c, r+1.5)
+	d.circle(fill, c, r)
+}
@@ -3,6 +3,7 @@ module github.com/quibbble/go-carcassonne
 go 1.21
 
 require (
+	github.com/gorilla/websocket v1.5.3
 	github.com/mitchellh/mapstructure v1.4.2
 	github.com/quibbble/go-boardgame v1.1.3
 	github.com/stretchr/testify v1.7.0
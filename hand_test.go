@@ -0,0 +1,136 @@
+package go_carcassonne
+
+import (
+	"math/rand"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Hand(t *testing.T) {
+	teams := []string{TeamA, TeamB}
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       teams,
+		MoreOptions: CarcassonneMoreOptions{Seed: 7, HandSize: 3},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	snapshot, _ := carcassonne.GetSnapshot(TeamA)
+	assert.NotNil(t, snapshot.MoreData.(CarcassonneSnapshotData).PlayTile)
+	assert.Len(t, snapshot.MoreData.(CarcassonneSnapshotData).Hand, 2)
+	// each team draws two more tiles than usual
+	single := newState(teams, 7, nil)
+	assert.Equal(t, len(single.deck.tiles)-4, len(carcassonne.state.deck.tiles))
+
+	// targets include placements for the other tiles in hand
+	slots := make(map[int]bool)
+	for _, target := range snapshot.Targets.([]*bg.BoardGameAction) {
+		if details, ok := target.MoreDetails.(PlaceTileActionDetails); ok {
+			slots[details.Slot] = true
+		}
+	}
+	assert.True(t, slots[1] || slots[2])
+
+	random := rand.New(rand.NewSource(7))
+	usedSlot := false
+	for len(carcassonne.state.winners) == 0 {
+		for _, team := range teams {
+			assert.LessOrEqual(t, len(carcassonne.state.hand(team)), 3)
+		}
+		action := randomAction(carcassonne.state, random)
+		if details, ok := action.MoreDetails.(PlaceTileActionDetails); ok && details.Slot > 0 {
+			usedSlot = true
+		}
+		if err := carcassonne.Do(action); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	assert.True(t, usedSlot)
+	for _, team := range teams {
+		assert.Empty(t, carcassonne.state.hand(team))
+	}
+
+	game := carcassonne.GetBGN()
+	assert.Equal(t, "3", game.Tags["HandSize"])
+	builder := Builder{}
+	loaded, err := builder.Load(game)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, carcassonne.state.board.board, loaded.(*Carcassonne).state.board.board)
+	assert.Equal(t, carcassonne.state.scores, loaded.(*Carcassonne).state.scores)
+}
+
+func Test_Hand_PlaceTile(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 7, HandSize: 2},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	other := carcassonne.state.hands[TeamA][0]
+	play := carcassonne.state.playTiles[TeamA]
+	var placement *PlaceTileActionDetails
+	for _, p := range carcassonne.state.placements(TeamA) {
+		if p.Slot == 1 {
+			placement = p
+			break
+		}
+	}
+	if placement == nil {
+		t.Error("no placement for slot 1")
+		t.FailNow()
+	}
+
+	// slots outside the hand are refused and leave the hand as it was
+	wrong := *placement
+	wrong.Slot = 2
+	assert.Error(t, carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceTile, MoreDetails: wrong}))
+	wrong.Slot = 0
+	if !play.equals(other) {
+		assert.Error(t, carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceTile, MoreDetails: wrong}))
+	}
+	assert.Equal(t, play, carcassonne.state.playTiles[TeamA])
+
+	if err := carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceTile, MoreDetails: *placement}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.True(t, carcassonne.state.board.tile(placement.X, placement.Y).equals(other))
+	details := carcassonne.GetBGN().Actions[0].Details
+	assert.Len(t, details, 5)
+	assert.Equal(t, "1", details[4])
+
+	// the tile not placed stays in hand and is joined by a newly drawn tile
+	if carcassonne.state.turn == TeamA {
+		if err := carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceToken, MoreDetails: PlaceTokenActionDetails{Pass: true}}); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	assert.Equal(t, play, carcassonne.state.playTiles[TeamA])
+	assert.Len(t, carcassonne.state.hands[TeamA], 1)
+
+	// positions keep every tile in hand
+	position := carcassonne.Position()
+	decoded, err := decodePosition([]string{TeamA, TeamB}, 7, position)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, position, encodePosition(decoded))
+}
+
+func Test_Hand_Invalid(t *testing.T) {
+	for _, size := range []int{-1, 4} {
+		_, err := NewCarcassonne(&bg.BoardGameOptions{Teams: []string{TeamA, TeamB}, MoreOptions: CarcassonneMoreOptions{HandSize: size}})
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,126 @@
+package go_carcassonne
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+	"github.com/quibbble/go-boardgame/pkg/bgn"
+)
+
+const (
+	// defaultReplayDelay is the time each replay frame is shown in hundredths of a second
+	defaultReplayDelay = 50
+
+	// defaultReplayEndDelay is the time the last replay frame is shown in hundredths of a second
+	defaultReplayEndDelay = 400
+)
+
+// RenderImage draws the current board and tokens of the game where each tile is 64 pixels wide multiplied by scale
+func (c *Carcassonne) RenderImage(scale float64) *image.RGBA {
+	return newDrawing(c.state.teams, c.state.board.board, c.state.boardTokens).rasterize(scale, rasterSamples)
+}
+
+// RenderPNG draws the current board and tokens of the game as a PNG image
+func (c *Carcassonne) RenderPNG(w io.Writer, scale float64) error {
+	return png.Encode(w, c.RenderImage(scale))
+}
+
+// RenderSnapshotPNG draws the board and tokens of a snapshot as a PNG image where teams are colored in the order given
+func RenderSnapshotPNG(w io.Writer, teams []string, data *CarcassonneSnapshotData, scale float64) error {
+	return png.Encode(w, newDrawing(teams, data.Board, data.BoardTokens).rasterize(scale, rasterSamples))
+}
+
+// ReplayOptions are the options for rendering a replay
+type ReplayOptions struct {
+	// Scale multiplies the 64 pixel width of each tile, defaults to 1
+	Scale float64
+
+	// Delay is the time each frame is shown in hundredths of a second
+	Delay int
+
+	// EndDelay is the time the final position is shown in hundredths of a second
+	EndDelay int
+}
+
+// RenderReplayGIF replays a game saved as BGN drawing an animated GIF with one frame for each tile and token placed
+func RenderReplayGIF(w io.Writer, game *bgn.Game, options *ReplayOptions) error {
+	if options == nil {
+		options = &ReplayOptions{}
+	}
+	scale, delay, endDelay := options.Scale, options.Delay, options.EndDelay
+	if scale <= 0 {
+		scale = 1
+	}
+	if delay <= 0 {
+		delay = defaultReplayDelay
+	}
+	if endDelay <= 0 {
+		endDelay = defaultReplayEndDelay
+	}
+	loaded, err := (&Builder{}).Load(game)
+	if err != nil {
+		return err
+	}
+	final := loaded.(*Carcassonne)
+	// every frame is the size of the final board so the board does not move as it grows
+	b := boardBounds(final.state.board.board)
+	replay, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       final.teams(),
+		MoreOptions: *final.options,
+	})
+	if err != nil {
+		return err
+	}
+	palette := replayPalette(replay.state.teams)
+	frame := func() *image.Paletted {
+		img := newDrawingWithin(replay.state.teams, replay.state.board.board, replay.state.boardTokens, b).rasterize(scale, 1)
+		paletted := image.NewPaletted(img.Bounds(), palette)
+		for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+			for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+				paletted.SetColorIndex(x, y, uint8(palette.Index(img.RGBAAt(x, y))))
+			}
+		}
+		return paletted
+	}
+	animation := &gif.GIF{}
+	addFrame := func() {
+		animation.Image = append(animation.Image, frame())
+		animation.Delay = append(animation.Delay, delay)
+	}
+	// the first frame includes any placements the automa made when the game was created
+	addFrame()
+	// frames come from the actions the replay applies as the automa plays its own placements
+	replay.onAction = func(action *bg.BoardGameAction) {
+		if action.ActionType == ActionPlaceTile || action.ActionType == ActionPlaceToken {
+			addFrame()
+		}
+	}
+	for i, action := range final.actions {
+		if err := replay.replay(i, action); err != nil {
+			return err
+		}
+	}
+	animation.Delay[len(animation.Delay)-1] = endDelay
+	if err := gif.EncodeAll(w, animation); err != nil {
+		return &bgerr.Error{
+			Err:    fmt.Errorf("failed to encode replay: %w", err),
+			Status: bgerr.StatusBGNEncodingFailure,
+		}
+	}
+	return nil
+}
+
+// replayPalette gets every color that can be drawn without smoothing edges
+func replayPalette(teams []string) color.Palette {
+	palette := color.Palette{backColor, farmColor, cityColor, roadColor, cloisterColor, bannerColor, gridColor, outlineColor}
+	for _, team := range teams {
+		palette = append(palette, teamColor(teams, team), mix(backColor, teamColor(teams, team)))
+	}
+	return palette
+}
@@ -0,0 +1,87 @@
+package go_carcassonne
+
+import (
+	"bytes"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/quibbble/go-boardgame/pkg/bgn"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenderPNG(t *testing.T) {
+	carcassonne := playRandom(t, []string{TeamA, TeamB, "TeamC"}, 7, 40)
+	var buf bytes.Buffer
+	if err := carcassonne.RenderPNG(&buf, 0.5); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	b := boardBounds(carcassonne.state.board.board)
+	assert.Equal(t, (b.maxX-b.minX+1)*drawTileSize/2, img.Bounds().Dx())
+	assert.Equal(t, (b.maxY-b.minY+1)*drawTileSize/2, img.Bounds().Dy())
+	golden(t, "board_middle.png", buf.Bytes())
+}
+
+func Test_RenderReplayGIF(t *testing.T) {
+	tests := []struct {
+		name        string
+		carcassonne *Carcassonne
+	}{
+		{name: "two teams", carcassonne: playRandom(t, []string{TeamA, TeamB}, 3, 1000)},
+		{name: "automa", carcassonne: playSolo(t, &SoloOptions{Automa: true}, 3)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			carcassonne := test.carcassonne
+			raw := carcassonne.GetBGN().String()
+			game, err := bgn.Parse(raw)
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			var buf bytes.Buffer
+			if err := RenderReplayGIF(&buf, game, &ReplayOptions{Scale: 0.25}); err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			animation, err := gif.DecodeAll(&buf)
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			// one frame for the start plus one for every tile and token placed, rotations have no frame
+			frames := 1
+			for _, action := range carcassonne.actions {
+				if action.ActionType == ActionPlaceTile || action.ActionType == ActionPlaceToken {
+					frames++
+				}
+			}
+			assert.Len(t, animation.Image, frames)
+			for _, frame := range animation.Image {
+				assert.Equal(t, animation.Image[0].Bounds(), frame.Bounds())
+			}
+			assert.Equal(t, defaultReplayDelay, animation.Delay[0])
+			assert.Equal(t, defaultReplayEndDelay, animation.Delay[len(animation.Delay)-1])
+
+			// the last frame matches the final board
+			last := animation.Image[len(animation.Image)-1]
+			final := newDrawing(carcassonne.state.teams, carcassonne.state.board.board, carcassonne.state.boardTokens).rasterize(0.25, 1)
+			for y := 0; y < final.Bounds().Dy(); y++ {
+				for x := 0; x < final.Bounds().Dx(); x++ {
+					r1, g1, b1, _ := last.At(x, y).RGBA()
+					r2, g2, b2, _ := final.At(x, y).RGBA()
+					if r1 != r2 || g1 != g2 || b1 != b2 {
+						t.Errorf("last frame differs from final board at %d,%d", x, y)
+						t.FailNow()
+					}
+				}
+			}
+		})
+	}
+}
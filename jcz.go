@@ -0,0 +1,429 @@
+package go_carcassonne
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgn"
+)
+
+// JCloisterZone action types
+const (
+	jczPlaceTile    = "PLACE_TILE"
+	jczDeployMeeple = "DEPLOY_MEEPLE"
+	jczPass         = "PASS"
+)
+
+const (
+	// jczAppVersion is the JCloisterZone version written when exporting
+	jczAppVersion = "5.0.0"
+
+	// jczBaseSet is the JCloisterZone tile set of the base game
+	jczBaseSet = "BA"
+
+	// jczSmallFollower is the only JCloisterZone meeple in the base game
+	jczSmallFollower = "small-follower"
+)
+
+var (
+	// jczTiles are the JCloisterZone ids of the base game tiles in the same order as tiles
+	// JCloisterZone names a tile by its features going clockwise round its edges where L is a cloister, F a farm edge
+	// between features, + a banner, and a lowercase letter another edge of the feature before it
+	jczTiles = []string{
+		"BA.L", "BA.LR", "BA.Cccc+", "BA.Ccc", "BA.Ccc+", "BA.CccR", "BA.CccR+", "BA.Cc", "BA.Cc+", "BA.CcRr",
+		"BA.CcRr+", "BA.CFc", "BA.CFc+", "BA.CC", "BA.CFC", "BA.C", "BA.CFRr", "BA.CRr", "BA.CRRR", "BA.RCr",
+		"BA.RFr", "BA.Rr", "BA.RRR", "BA.RRRR",
+	}
+
+	// jczStartTile is the id JCloisterZone uses for the start tile
+	jczStartTile = "BA.RCr"
+
+	// jczExpansions are the names of JCloisterZone tile sets and elements from expansions that are not supported
+	jczExpansions = map[string]string{
+		"IC": "Inns & Cathedrals", "TB": "Traders & Builders", "PD": "The Princess & The Dragon", "TO": "The Tower",
+		"AM": "Abbey & Mayor", "CCO": "Count, King & Robber", "BB": "Bridges, Castles & Bazaars", "HS": "Hills & Sheep",
+		"R1": "The River", "R2": "The River II", "GQ11": "Mini Expansions",
+		"big-follower": "Inns & Cathedrals", "builder": "Traders & Builders", "pig": "Traders & Builders",
+		"dragon": "The Princess & The Dragon", "fairy": "The Princess & The Dragon", "tower": "The Tower",
+		"abbey": "Abbey & Mayor", "mayor": "Abbey & Mayor", "wagon": "Abbey & Mayor", "barn": "Abbey & Mayor",
+		"phantom": "Phantom", "abbot": "Abbot", "shepherd": "Hills & Sheep",
+	}
+
+	// jczElements are the JCloisterZone elements of the base game
+	jczElements = map[string]bool{jczSmallFollower: true, "farmers": true}
+
+	sideToJCZ = map[string]string{SideTop: "N", SideRight: "E", SideBottom: "S", SideLeft: "W"}
+	jczToSide = reverseMap(sideToJCZ)
+
+	// farm sides are named by the edge followed by the half on the left or right when facing that edge from the center
+	farmSideToJCZ = map[string]string{FarmSideTopA: "NL", FarmSideTopB: "NR", FarmSideRightA: "EL", FarmSideRightB: "ER", FarmSideBottomA: "SL", FarmSideBottomB: "SR", FarmSideLeftA: "WL", FarmSideLeftB: "WR"}
+	jczToFarmSide = reverseMap(farmSideToJCZ)
+
+	rotationToJCZ = []string{"R0", "R90", "R180", "R270"}
+)
+
+// jczGame is the part of a JCloisterZone save needed to replay a game
+type jczGame struct {
+	AppVersion  string          `json:"appVersion"`
+	InitialSeed int64           `json:"initialSeed"`
+	Setup       jczSetup        `json:"setup"`
+	Players     []jczPlayer     `json:"players"`
+	Replay      []jczGameAction `json:"replay"`
+}
+
+type jczSetup struct {
+	Elements map[string]json.RawMessage `json:"elements"`
+	Tiles    map[string]json.RawMessage `json:"tiles"`
+	Start    []jczPlacement             `json:"start,omitempty"`
+}
+
+type jczPlayer struct {
+	Name string `json:"name"`
+	Slot int    `json:"slot"`
+}
+
+type jczGameAction struct {
+	Type    string          `json:"type"`
+	Player  int             `json:"player"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type jczPlacement struct {
+	Tile     string `json:"tileId"`
+	Rotation string `json:"rotation"`
+	Position [2]int `json:"position"`
+}
+
+type jczDeployment struct {
+	Pointer    jczPointer `json:"pointer"`
+	MeepleID   string     `json:"meepleId,omitempty"`
+	MeepleType string     `json:"meepleType,omitempty"`
+}
+
+type jczPointer struct {
+	Position [2]int `json:"position"`
+	Location string `json:"location"`
+}
+
+// ImportJCZ reads a JCloisterZone save of a base game into a game that can be loaded with Builder.Load
+// JCloisterZone y coordinates increase downwards so are flipped
+func ImportJCZ(r io.Reader) (*bgn.Game, error) {
+	var save jczGame
+	if err := json.NewDecoder(r).Decode(&save); err != nil {
+		return nil, loadFailure(fmt.Errorf("failed to read JCloisterZone save: %w", err))
+	}
+	if err := save.validateSetup(); err != nil {
+		return nil, loadFailure(err)
+	}
+	teams := jczTeams(save.Players)
+
+	// tiles are placed in the order they are drawn so the placed tiles are the deck order
+	order := make([]int, 0)
+	for i, action := range save.Replay {
+		if action.Type != jczPlaceTile {
+			continue
+		}
+		placement, err := action.placement()
+		if err != nil {
+			return nil, loadFailure(fmt.Errorf("replay action %d: %w", i, err))
+		}
+		typ, _, err := placement.tileType()
+		if err != nil {
+			return nil, loadFailure(fmt.Errorf("replay action %d: %w", i, err))
+		}
+		order = append(order, typ)
+	}
+	if err := validateDeckOrder(order); err != nil {
+		return nil, loadFailure(err)
+	}
+	game, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       teams,
+		MoreOptions: CarcassonneMoreOptions{Seed: save.InitialSeed, Deck: order},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, action := range save.Replay {
+		if err := game.doJCZ(teams, action); err != nil {
+			return nil, loadFailure(fmt.Errorf("replay action %d: %w", i, err))
+		}
+	}
+	return game.GetBGN(), nil
+}
+
+// ExportJCZ writes a game as a JCloisterZone save
+func ExportJCZ(w io.Writer, game *bgn.Game) error {
+	builder := Builder{}
+	loaded, err := builder.Load(game)
+	if err != nil {
+		return err
+	}
+	c := loaded.(*Carcassonne)
+	if c.options.Position != "" || c.options.Solo != nil {
+		return fmt.Errorf("cannot export a solo game or a game started from a position to JCloisterZone")
+	} else if c.options.HandSize > 1 {
+		return fmt.Errorf("cannot export a game with hands of tiles to JCloisterZone")
+	}
+	save := jczGame{
+		AppVersion:  jczAppVersion,
+		InitialSeed: c.options.Seed,
+		Setup: jczSetup{
+			Elements: map[string]json.RawMessage{jczSmallFollower: json.RawMessage("7"), "farmers": json.RawMessage("true")},
+			Tiles:    map[string]json.RawMessage{jczBaseSet: json.RawMessage("1")},
+			Start:    []jczPlacement{{Tile: jczStartTile, Rotation: rotationToJCZ[0]}},
+		},
+		Players: make([]jczPlayer, 0, len(c.state.teams)),
+		Replay:  make([]jczGameAction, 0, len(c.actions)),
+	}
+	for i, team := range c.state.teams {
+		save.Players = append(save.Players, jczPlayer{Name: team, Slot: i})
+	}
+	for _, action := range c.actions {
+		var payload interface{}
+		exported := jczGameAction{Player: indexOf(c.state.teams, action.Team)}
+		switch action.ActionType {
+		case ActionRotateTileRight, ActionRotateTileLeft:
+			// placed tiles include their rotation
+			continue
+		case ActionPlaceTile:
+			var details PlaceTileActionDetails
+			_ = mapstructure.Decode(action.MoreDetails, &details)
+			typ, rotation, ok := tileType(details.Tile)
+			if !ok {
+				return fmt.Errorf("tile %+v is not a base game tile", details.Tile)
+			}
+			exported.Type = jczPlaceTile
+			payload = jczPlacement{Tile: jczTiles[typ], Rotation: rotationToJCZ[rotation], Position: [2]int{details.X, -details.Y}}
+		case ActionPlaceToken:
+			var details PlaceTokenActionDetails
+			_ = mapstructure.Decode(action.MoreDetails, &details)
+			if details.Pass {
+				exported.Type = jczPass
+				break
+			}
+			location := "CLOISTER"
+			switch details.Type {
+			case Knight, Thief:
+				location = sideToJCZ[details.Side]
+			case Farmer:
+				location = farmSideToJCZ[details.Side]
+			}
+			exported.Type = jczDeployMeeple
+			payload = jczDeployment{
+				Pointer:    jczPointer{Position: [2]int{details.X, -details.Y}, Location: location},
+				MeepleType: jczSmallFollower,
+			}
+		default:
+			return fmt.Errorf("cannot export %s action to JCloisterZone", action.ActionType)
+		}
+		if payload != nil {
+			raw, err := json.Marshal(payload)
+			if err != nil {
+				return err
+			}
+			exported.Payload = raw
+		}
+		save.Replay = append(save.Replay, exported)
+	}
+	raw, err := json.MarshalIndent(save, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(raw, '\n'))
+	return err
+}
+
+// validateSetup checks the save only uses the base game
+func (g *jczGame) validateSetup() error {
+	if len(g.Players) < minTeams || len(g.Players) > maxTeams {
+		return fmt.Errorf("got %d players but wanted %d to %d", len(g.Players), minTeams, maxTeams)
+	}
+	if len(g.Setup.Tiles) > 0 && !jczEnabled(g.Setup.Tiles[jczBaseSet]) {
+		return fmt.Errorf("save does not use the base game tiles")
+	}
+	for _, set := range sortedKeys(g.Setup.Tiles) {
+		if set != jczBaseSet && jczEnabled(g.Setup.Tiles[set]) {
+			return jczUnsupported(set)
+		}
+	}
+	for _, element := range sortedKeys(g.Setup.Elements) {
+		raw := g.Setup.Elements[element]
+		if !jczElements[element] && jczEnabled(raw) {
+			return jczUnsupported(element)
+		}
+		if element == jczSmallFollower && string(raw) != "7" {
+			return fmt.Errorf("got %s small followers but only games with 7 are supported", string(raw))
+		}
+	}
+	for _, start := range g.Setup.Start {
+		typ, rotation, err := start.tileType()
+		if err != nil {
+			return err
+		}
+		if typ != jczStart() || rotation != 0 || start.Position != [2]int{0, 0} || len(g.Setup.Start) != 1 {
+			return fmt.Errorf("only games starting with the start tile %s at 0,0 are supported", jczStartTile)
+		}
+	}
+	return nil
+}
+
+// doJCZ does the JCloisterZone action where passes are skipped if the game already passed on its own
+func (c *Carcassonne) doJCZ(teams []string, action jczGameAction) error {
+	if action.Player < 0 || action.Player >= len(teams) {
+		return fmt.Errorf("player %d out of range", action.Player)
+	}
+	team := teams[action.Player]
+	switch action.Type {
+	case jczPlaceTile:
+		placement, err := action.placement()
+		if err != nil {
+			return err
+		}
+		typ, rotation, err := placement.tileType()
+		if err != nil {
+			return err
+		}
+		t := tiles[typ].tile.copy()
+		for i := 0; i < rotation; i++ {
+			t.RotateRight()
+		}
+		return c.Do(&bg.BoardGameAction{
+			Team:       team,
+			ActionType: ActionPlaceTile,
+			MoreDetails: PlaceTileActionDetails{
+				X:    placement.Position[0],
+				Y:    -placement.Position[1],
+				Tile: tileToActionDetails(t),
+			},
+		})
+	case jczDeployMeeple:
+		var deployment jczDeployment
+		if err := json.Unmarshal(action.Payload, &deployment); err != nil {
+			return err
+		}
+		details, err := c.jczTokenDetails(deployment)
+		if err != nil {
+			return err
+		}
+		return c.Do(&bg.BoardGameAction{Team: team, ActionType: ActionPlaceToken, MoreDetails: *details})
+	case jczPass:
+		if c.state.turn != team || c.state.playTiles[team] != nil || len(c.state.winners) > 0 {
+			return nil
+		}
+		return c.Do(&bg.BoardGameAction{Team: team, ActionType: ActionPlaceToken, MoreDetails: PlaceTokenActionDetails{Pass: true}})
+	default:
+		return fmt.Errorf("unsupported action %s which is only used by expansions", action.Type)
+	}
+}
+
+// jczTokenDetails gets the token to place from where the meeple was deployed
+func (c *Carcassonne) jczTokenDetails(deployment jczDeployment) (*PlaceTokenActionDetails, error) {
+	meeple := deployment.MeepleType
+	if meeple == "" {
+		meeple = deployment.MeepleID
+	}
+	if meeple != "" && !strings.Contains(strings.ToLower(meeple), jczSmallFollower) && !strings.Contains(meeple, "SmallFollower") {
+		return nil, fmt.Errorf("unsupported meeple %s which is only used by expansions", meeple)
+	}
+	x, y := deployment.Pointer.Position[0], -deployment.Pointer.Position[1]
+	placed := c.state.board.tile(x, y)
+	if placed == nil {
+		return nil, fmt.Errorf("no tile at %d,%d", deployment.Pointer.Position[0], deployment.Pointer.Position[1])
+	}
+	// a location covering many edges is written like N.E so the first edge is used
+	location := strings.ToUpper(strings.Split(deployment.Pointer.Location, ".")[0])
+	if location == "CLOISTER" {
+		return &PlaceTokenActionDetails{X: x, Y: y, Type: Monk}, nil
+	}
+	if farmSide, ok := jczToFarmSide[location]; ok {
+		return &PlaceTokenActionDetails{X: x, Y: y, Type: Farmer, Side: farmSide}, nil
+	}
+	side, ok := jczToSide[location[:min(len(location), 1)]]
+	if !ok {
+		return nil, fmt.Errorf("unsupported location %s", deployment.Pointer.Location)
+	}
+	switch placed.Sides[side] {
+	case City:
+		return &PlaceTokenActionDetails{X: x, Y: y, Type: Knight, Side: side}, nil
+	case Road:
+		return &PlaceTokenActionDetails{X: x, Y: y, Type: Thief, Side: side}, nil
+	}
+	return nil, fmt.Errorf("no city or road at location %s of tile at %d,%d", deployment.Pointer.Location, deployment.Pointer.Position[0], deployment.Pointer.Position[1])
+}
+
+func (a jczGameAction) placement() (*jczPlacement, error) {
+	var placement jczPlacement
+	if err := json.Unmarshal(a.Payload, &placement); err != nil {
+		return nil, err
+	}
+	return &placement, nil
+}
+
+// tileType gets the index in tiles and the number of right rotations of the placed tile
+func (p *jczPlacement) tileType() (int, int, error) {
+	typ := indexOf(jczTiles, p.Tile)
+	if typ < 0 {
+		if set := strings.Split(p.Tile, ".")[0]; set != jczBaseSet {
+			return 0, 0, jczUnsupported(set)
+		}
+		return 0, 0, fmt.Errorf("unknown base game tile %s", p.Tile)
+	}
+	rotation := indexOf(rotationToJCZ, p.Rotation)
+	if rotation < 0 {
+		return 0, 0, fmt.Errorf("got rotation %s but wanted one of %s", p.Rotation, strings.Join(rotationToJCZ, ", "))
+	}
+	return typ, rotation, nil
+}
+
+// jczStart gets the index in tiles of the start tile
+func jczStart() int {
+	typ, _, _ := tileType(tileToActionDetails(startTile))
+	return typ
+}
+
+// jczTeams gets unique team names from the players
+func jczTeams(players []jczPlayer) []string {
+	teams := make([]string, 0, len(players))
+	for i, player := range players {
+		// team names are joined with commas in BGN
+		name := strings.TrimSpace(strings.ReplaceAll(player.Name, ",", ""))
+		if name == "" {
+			name = "Player " + strconv.Itoa(i+1)
+		}
+		unique := name
+		for n := 2; contains(teams, unique); n++ {
+			unique = name + " " + strconv.Itoa(n)
+		}
+		teams = append(teams, unique)
+	}
+	return teams
+}
+
+// jczEnabled checks whether a tile set or element is used where it may be a count, a flag, or settings
+func jczEnabled(raw json.RawMessage) bool {
+	value := string(bytes.TrimSpace(raw))
+	return value != "" && value != "0" && value != "false" && value != "null"
+}
+
+func jczUnsupported(name string) error {
+	if expansion, ok := jczExpansions[name]; ok {
+		return fmt.Errorf("unsupported expansion %s (%s), only the base game is supported", expansion, name)
+	}
+	return fmt.Errorf("unsupported expansion %s, only the base game is supported", name)
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
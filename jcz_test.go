@@ -0,0 +1,209 @@
+package go_carcassonne
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_JCZ(t *testing.T) {
+	tests := []struct {
+		name     string
+		save     string
+		err      string
+		expected []string
+	}{
+		{
+			name: "place tile and meeple then pass",
+			save: `{
+				"appVersion": "5.0.0",
+				"setup": {"elements": {"small-follower": 7, "farmers": true}, "tiles": {"BA": 1}, "start": [{"tileId": "BA.RCr", "rotation": "R0", "position": [0, 0]}]},
+				"players": [{"name": "Red", "slot": 0}, {"name": "Blue", "slot": 1}],
+				"replay": [
+					{"type": "PLACE_TILE", "player": 0, "payload": {"tileId": "BA.Rr", "rotation": "R0", "position": [1, 0]}},
+					{"type": "DEPLOY_MEEPLE", "player": 0, "payload": {"pointer": {"position": [1, 0], "location": "S"}, "meepleType": "small-follower"}},
+					{"type": "PLACE_TILE", "player": 1, "payload": {"tileId": "BA.L", "rotation": "R90", "position": [0, 1]}},
+					{"type": "PASS", "player": 1},
+					{"type": "PLACE_TILE", "player": 0, "payload": {"tileId": "BA.C", "rotation": "R180", "position": [0, -1]}},
+					{"type": "DEPLOY_MEEPLE", "player": 0, "payload": {"pointer": {"position": [0, -1], "location": "EL"}, "meepleId": "1.small-follower.1"}}
+				]
+			}`,
+			expected: []string{"0i&1.0.21.0", "0o&f.1.0.t.b", "1i&0.-1.0.0", "1o&t", "0i&0.1.15.2", "0o&f.0.1.f.ra"},
+		},
+		{
+			name: "unsupported tile set",
+			save: `{"setup": {"tiles": {"BA": 1, "IC": 1}}, "players": [{"name": "Red"}, {"name": "Blue"}]}`,
+			err:  "unsupported expansion Inns & Cathedrals (IC)",
+		},
+		{
+			name: "unsupported element",
+			save: `{"setup": {"elements": {"small-follower": 7, "abbot": true}, "tiles": {"BA": 1}}, "players": [{"name": "Red"}, {"name": "Blue"}]}`,
+			err:  "unsupported expansion Abbot (abbot)",
+		},
+		{
+			name: "unsupported tile",
+			save: `{"setup": {"tiles": {"BA": 1}}, "players": [{"name": "Red"}, {"name": "Blue"}],
+				"replay": [{"type": "PLACE_TILE", "player": 0, "payload": {"tileId": "TB.CCRR", "rotation": "R0", "position": [1, 0]}}]}`,
+			err: "unsupported expansion Traders & Builders (TB)",
+		},
+		{
+			name: "unsupported action",
+			save: `{"setup": {"tiles": {"BA": 1}}, "players": [{"name": "Red"}, {"name": "Blue"}],
+				"replay": [{"type": "PLACE_TOKEN", "player": 0, "payload": {}}]}`,
+			err: "unsupported action PLACE_TOKEN",
+		},
+		{
+			name: "too few players",
+			save: `{"setup": {"tiles": {"BA": 1}}, "players": [{"name": "Red"}]}`,
+			err:  "got 1 players but wanted 2 to 5",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := ImportJCZ(strings.NewReader(test.save))
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			actions := make([]string, 0)
+			for _, action := range game.Actions {
+				actions = append(actions, action.String())
+			}
+			assert.Equal(t, test.expected, actions)
+			builder := Builder{}
+			if _, err := builder.Load(game); err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+		})
+	}
+}
+
+func Test_JCZ_RoundTrip(t *testing.T) {
+	for _, seed := range []int64{1, 2, 3} {
+		carcassonne := playRandom(t, []string{TeamA, TeamB, "TeamC"}, seed, 1000)
+		var buf bytes.Buffer
+		if err := ExportJCZ(&buf, carcassonne.GetBGN()); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		game, err := ImportJCZ(&buf)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		builder := Builder{}
+		loaded, err := builder.Load(game)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		imported := loaded.(*Carcassonne)
+		assert.Equal(t, carcassonne.state.scores, imported.state.scores)
+		assert.Equal(t, carcassonne.state.winners, imported.state.winners)
+		assert.Equal(t, len(carcassonne.state.board.board), len(imported.state.board.board))
+		assert.Equal(t, len(carcassonne.state.boardTokens), len(imported.state.boardTokens))
+	}
+}
+
+func Test_Builder_Deck(t *testing.T) {
+	builder := Builder{}
+	game, err := builder.CreateWithBGN(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 3, Deck: []int{21, 0, 23}},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	carcassonne := game.(*Carcassonne)
+	assert.True(t, carcassonne.state.playTiles[TeamA].equals(tiles[21].tile))
+	assert.True(t, carcassonne.state.playTiles[TeamB].equals(tiles[0].tile))
+	assert.Equal(t, "21.0.23", game.GetBGN().Tags["Deck"])
+
+	loaded, err := builder.Load(game.GetBGN())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, []int{21, 0, 23}, loaded.(*Carcassonne).options.Deck)
+
+	_, err = builder.Create(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Deck: []int{23, 23}},
+	})
+	assert.Error(t, err)
+}
+
+// base.jcz is written by hand following the save format rather than exported by JCloisterZone
+func Test_JCZ_Save(t *testing.T) {
+	file, err := os.Open("testdata/jcz/base.jcz")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer file.Close()
+	game, err := ImportJCZ(file)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, "Red, Blue", game.Tags["Teams"])
+	assert.Equal(t, "21.15.0.20.7", game.Tags["Deck"])
+	builder := Builder{}
+	loaded, err := builder.Load(game)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	carcassonne := loaded.(*Carcassonne)
+	assert.Equal(t, 6, len(carcassonne.state.board.board))
+	assert.Equal(t, map[string]int{"Red": 0, "Blue": 4}, carcassonne.state.scores)
+	assert.Equal(t, 5, carcassonne.state.tokens["Red"])
+	assert.Equal(t, 7, carcassonne.state.tokens["Blue"])
+
+	// JCloisterZone y points down so each tile is found at the negated y with its sides top, right, bottom, left
+	expected := []struct {
+		x, y  int
+		sides []string
+	}{
+		{x: 0, y: 0, sides: []string{City, Road, Farm, Road}},
+		{x: 1, y: 0, sides: []string{Farm, Farm, Road, Road}},
+		{x: 0, y: 1, sides: []string{Farm, Farm, City, Farm}},
+		{x: 0, y: -1, sides: []string{Farm, Farm, Farm, Farm}},
+		{x: -1, y: 0, sides: []string{Farm, Road, Farm, Road}},
+		{x: 1, y: 1, sides: []string{City, City, Farm, Farm}},
+	}
+	for i, tile := range carcassonne.state.board.board {
+		assert.Equal(t, expected[i].x, tile.X)
+		assert.Equal(t, expected[i].y, tile.Y)
+		assert.Equal(t, expected[i].sides, []string{tile.Sides[SideTop], tile.Sides[SideRight], tile.Sides[SideBottom], tile.Sides[SideLeft]})
+	}
+	assert.Equal(t, Cloister, carcassonne.state.board.tile(0, -1).Center)
+	assert.Equal(t, []*token{newToken(1, 0, "Red", Thief, SideLeft), newToken(0, -1, "Red", Monk, "")}, carcassonne.state.boardTokens)
+}
+
+func Test_Deck_FixedOrderRedraw(t *testing.T) {
+	s := newState([]string{TeamA, TeamB}, 5, []int{21, 0, 23, 15, 20})
+	s.playTiles[TeamA] = nil
+	s.playTiles[TeamB] = newTile("Lake", "Lake", "Lake", "Lake", NilStructure, false, false)
+	if err := s.nextTurn(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.True(t, s.playTiles[TeamB].equals(tiles[23].tile))
+	for _, typ := range []int{15, 20} {
+		drawn, _ := s.deck.Draw()
+		assert.True(t, drawn.equals(tiles[typ].tile))
+	}
+}
@@ -0,0 +1,412 @@
+package go_carcassonne
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	bg "github.com/quibbble/go-boardgame"
+)
+
+const (
+	defaultMCTSIterations  = 1000
+	defaultMCTSExploration = 0.7
+	defaultMCTSCandidates  = 8
+
+	// mctsRewardScale is the point difference at which a team is considered to be clearly winning
+	mctsRewardScale = 10.
+)
+
+// MCTSBotOptions are the options for creating a MCTSBot
+type MCTSBotOptions struct {
+	// Iterations is the number of search iterations to perform for each action split evenly between the workers
+	// if both Iterations and Duration are zero then a default number of iterations is used
+	Iterations int
+
+	// Duration is the max amount of time to search for each action, zero for no limit
+	Duration time.Duration
+
+	// Workers is the number of goroutines searching in parallel, defaults to one
+	// without a Duration the search is deterministic for a Seed whatever the number of workers
+	Workers int
+
+	// Exploration is the UCT exploration constant, higher values explore more
+	Exploration float64
+
+	// Candidates is the number of most promising moves searched, a negative value searches every move
+	Candidates int
+
+	// RolloutTurns is the number of turns played randomly after leaving the search tree before evaluating the game
+	// zero evaluates the game as soon as the search tree is left and a negative value plays until the end of the game
+	RolloutTurns int
+
+	// Seed used to generate deterministic randomness
+	Seed int64
+}
+
+// MCTSBot uses information set Monte Carlo tree search to choose actions
+// The hidden deck and play tiles of other teams are handled by searching over random determinizations
+// of the tiles team has not yet seen
+type MCTSBot struct {
+	options MCTSBotOptions
+	random  *rand.Rand
+}
+
+func NewMCTSBot(options *MCTSBotOptions) *MCTSBot {
+	details := *options
+	if details.Iterations <= 0 && details.Duration <= 0 {
+		details.Iterations = defaultMCTSIterations
+	}
+	if details.Workers <= 0 {
+		details.Workers = 1
+	}
+	if details.Exploration <= 0 {
+		details.Exploration = defaultMCTSExploration
+	}
+	if details.Candidates == 0 {
+		details.Candidates = defaultMCTSCandidates
+	}
+	return &MCTSBot{
+		options: details,
+		random:  rand.New(rand.NewSource(details.Seed)),
+	}
+}
+
+func (b *MCTSBot) Action(game *Carcassonne, team string) (*bg.BoardGameAction, error) {
+	return b.ActionContext(context.Background(), game, team)
+}
+
+// ActionContext searches for the best action until the iteration or time budget is used up or ctx is done
+func (b *MCTSBot) ActionContext(ctx context.Context, game *Carcassonne, team string) (*bg.BoardGameAction, error) {
+	candidates, err := mctsCandidates(game, team, b.options.Candidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 1 {
+		return candidates[0][0], nil
+	}
+	if b.options.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.options.Duration)
+		defer cancel()
+	}
+
+	// root parallelization where each worker builds its own tree and the root statistics are combined
+	unseen := game.state.unseenTiles(team)
+	roots := make([]*mctsNode, b.options.Workers)
+	errs := make([]error, b.options.Workers)
+	var wg sync.WaitGroup
+	for i := range roots {
+		roots[i] = newMCTSNode(nil, nil)
+		for _, candidate := range candidates {
+			roots[i].children[mctsKey(candidate...)] = newMCTSNode(roots[i], candidate)
+		}
+		search := &mctsSearch{
+			game:        game,
+			team:        team,
+			unseen:      unseen,
+			exploration: b.options.Exploration,
+			rollout:     b.options.RolloutTurns,
+			random:      rand.New(rand.NewSource(b.random.Int63())),
+		}
+		// each worker has its own share of the iterations so results do not depend on how the workers are scheduled
+		budget := b.options.Iterations / b.options.Workers
+		if i < b.options.Iterations%b.options.Workers {
+			budget++
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for n := 0; ctx.Err() == nil; n++ {
+				if b.options.Iterations > 0 && n >= budget {
+					return
+				}
+				if err := search.iterate(roots[i]); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// choose the first action of the most visited candidate
+	visits := make(map[string]int)
+	for _, root := range roots {
+		for key, child := range root.children {
+			visits[key] += child.visits
+		}
+	}
+	best := candidates[0]
+	for _, candidate := range candidates {
+		if visits[mctsKey(candidate...)] > visits[mctsKey(best...)] {
+			best = candidate
+		}
+	}
+	return best[0], nil
+}
+
+// mctsCandidates gets the most promising moves for team to search where a move is the tile placement
+// and the token placement that follows it or just the token placement if the tile is already placed
+// moves are ranked by how far ahead of the other teams team would be after the move
+func mctsCandidates(game *Carcassonne, team string, limit int) ([][]*bg.BoardGameAction, error) {
+	actions, err := game.state.moves(team)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([][]*bg.BoardGameAction, 0)
+	values := make(map[string]float64)
+	for _, action := range actions {
+		clone := game.searchClone()
+		if err := clone.Do(action); err != nil {
+			return nil, err
+		}
+		moves := [][]*bg.BoardGameAction{{action}}
+		games := []*Carcassonne{clone}
+		if len(clone.state.winners) == 0 && clone.state.turn == team && clone.state.playTiles[team] == nil {
+			tokens, err := clone.state.moves(team)
+			if err != nil {
+				return nil, err
+			}
+			moves, games = nil, nil
+			for _, token := range tokens {
+				next := clone.searchClone()
+				if err := next.Do(token); err != nil {
+					return nil, err
+				}
+				moves = append(moves, []*bg.BoardGameAction{action, token})
+				games = append(games, next)
+			}
+		}
+		for i, move := range moves {
+			rewards, err := mctsRewards(games[i])
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, move)
+			values[mctsKey(move...)] = rewards[team]
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return values[mctsKey(candidates[i]...)] > values[mctsKey(candidates[j]...)]
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// mctsNode is a node in the search tree reached by performing actions
+type mctsNode struct {
+	actions   []*bg.BoardGameAction
+	parent    *mctsNode
+	children  map[string]*mctsNode
+	visits    int
+	available int     // number of times this node could have been selected
+	reward    float64 // total reward for the team that performed actions
+}
+
+func newMCTSNode(parent *mctsNode, actions []*bg.BoardGameAction) *mctsNode {
+	return &mctsNode{
+		actions:  actions,
+		parent:   parent,
+		children: make(map[string]*mctsNode),
+	}
+}
+
+// ucb gets the upper confidence bound used to select which child to explore
+func (n *mctsNode) ucb(exploration float64) float64 {
+	if n.visits == 0 {
+		return math.Inf(1)
+	}
+	return n.reward/float64(n.visits) + exploration*math.Sqrt(math.Log(float64(n.available))/float64(n.visits))
+}
+
+// mctsSearch holds everything a single worker needs to perform search iterations
+type mctsSearch struct {
+	game        *Carcassonne
+	team        string
+	unseen      []*tile
+	exploration float64
+	rollout     int
+	random      *rand.Rand
+}
+
+// iterate performs a single select, expand, rollout, and backpropagate step on a new determinization of the game
+func (m *mctsSearch) iterate(root *mctsNode) error {
+	game := m.determinize()
+	// the root candidates are always available
+	for _, child := range root.children {
+		child.available++
+	}
+	node := root.best(root.children, m.exploration)
+	for _, action := range node.actions {
+		if err := game.Do(action); err != nil {
+			return err
+		}
+	}
+	// selection and expansion
+	for expanded := node.visits == 0; !expanded && len(game.state.winners) == 0; {
+		actions, err := game.state.moves(game.state.turn)
+		if err != nil {
+			return err
+		}
+		untried := make([]*bg.BoardGameAction, 0)
+		available := make(map[string]*mctsNode)
+		for _, action := range actions {
+			key := mctsKey(action)
+			if child, ok := node.children[key]; ok {
+				child.available++
+				available[key] = child
+			} else {
+				untried = append(untried, action)
+			}
+		}
+		var selected *mctsNode
+		if len(untried) > 0 {
+			action := untried[m.random.Intn(len(untried))]
+			selected = newMCTSNode(node, []*bg.BoardGameAction{action})
+			selected.available++
+			node.children[mctsKey(action)] = selected
+			expanded = true
+		} else {
+			selected = node.best(available, m.exploration)
+		}
+		if err := game.Do(selected.actions[0]); err != nil {
+			return err
+		}
+		node = selected
+	}
+	// rollout
+	for turns := 0; len(game.state.winners) == 0 && (m.rollout < 0 || turns < m.rollout); {
+		team := game.state.turn
+		actions, err := game.state.moves(team)
+		if err != nil {
+			return err
+		}
+		if err := game.Do(actions[m.random.Intn(len(actions))]); err != nil {
+			return err
+		}
+		if game.state.turn != team {
+			turns++
+		}
+	}
+	// backpropagation
+	rewards, err := mctsRewards(game)
+	if err != nil {
+		return err
+	}
+	for ; node != nil; node = node.parent {
+		node.visits++
+		if node.actions != nil {
+			node.reward += rewards[node.actions[0].Team]
+		}
+	}
+	return nil
+}
+
+// best gets the child with the highest upper confidence bound breaking ties by key for determinism
+func (n *mctsNode) best(children map[string]*mctsNode, exploration float64) *mctsNode {
+	var best *mctsNode
+	bestKey := ""
+	for key, child := range children {
+		if best == nil || child.ucb(exploration) > best.ucb(exploration) ||
+			(child.ucb(exploration) == best.ucb(exploration) && key < bestKey) {
+			best = child
+			bestKey = key
+		}
+	}
+	return best
+}
+
+// determinize creates a new determinization of the game for an iteration
+func (m *mctsSearch) determinize() *Carcassonne {
+	return determinize(m.game, m.team, m.unseen, m.random)
+}
+
+// mctsRewards gets a reward between zero and one for each team based on how far ahead or behind the team is
+func mctsRewards(game *Carcassonne) (map[string]float64, error) {
+	values, err := mctsValues(game.state)
+	if err != nil {
+		return nil, err
+	}
+	rewards := make(map[string]float64)
+	for _, team := range game.state.teams {
+		best := math.Inf(-1)
+		for _, other := range game.state.teams {
+			if other != team && values[other] > best {
+				best = values[other]
+			}
+		}
+		rewards[team] = 1 / (1 + math.Exp(-(values[team]-best)/mctsRewardScale))
+	}
+	return rewards, nil
+}
+
+// mctsValues estimates the final score of each team by adding the expected value of incomplete structures to the current scores
+// incomplete cities and cloisters are worth more the more likely they are to be completed while roads grow the more open sides they have and the more tiles remain
+func mctsValues(s *state) (map[string]float64, error) {
+	values := make(map[string]float64, len(s.scores))
+	for team, score := range s.scores {
+		values[team] = float64(score)
+	}
+	if len(s.winners) > 0 {
+		return values, nil
+	}
+	results, err := s.endGameScoring()
+	if err != nil {
+		return nil, err
+	}
+	remaining := float64(s.deck.Size()) / float64(totalTiles)
+	counts, left := s.tilesLeft()
+	for _, result := range results {
+		value := float64(result.points)
+		switch result.typ {
+		case City:
+			// a complete city is worth double
+			value += value * s.scoringProbability(result, counts, left)
+		case Road:
+			value += float64(openSides(result.structure)) * remaining
+		case Cloister:
+			value += float64(9-result.points) * s.scoringProbability(result, counts, left)
+		}
+		for _, winner := range result.winners {
+			values[winner] += value
+		}
+	}
+	for team, tokens := range s.tokens {
+		values[team] += 2 * tokenWeight * remaining * float64(tokens)
+	}
+	return values, nil
+}
+
+// openSides gets the number of sides of a city or road structure that are not yet connected to another tile
+func openSides(structure *structure) int {
+	open := 0
+	for _, n := range structure.nodes {
+		for _, side := range n.sides {
+			if n.tile.adjacent[side] == nil {
+				open++
+			}
+		}
+	}
+	return open
+}
+
+// mctsKey uniquely identifies a sequence of actions within a single turn
+func mctsKey(actions ...*bg.BoardGameAction) string {
+	key := ""
+	for _, action := range actions {
+		key += fmt.Sprintf("%s%+v", action.ActionType, action.MoreDetails)
+	}
+	return key
+}
@@ -0,0 +1,104 @@
+package go_carcassonne
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MCTSBot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping self-play benchmark in short mode")
+	}
+	games := 16
+	scores := make([]map[string]int, games)
+	t.Run("games", func(t *testing.T) {
+		for seed := int64(0); seed < int64(games); seed++ {
+			seed := seed
+			t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+				t.Parallel()
+				// rotate seats so both bots play first
+				mcts, greedy := TeamA, TeamB
+				if seed%2 == 1 {
+					mcts, greedy = TeamB, TeamA
+				}
+				bots := map[string]Bot{
+					mcts:   NewMCTSBot(&MCTSBotOptions{Iterations: 60, Workers: 2, Candidates: 6, Seed: seed}),
+					greedy: NewGreedyBot(seed),
+				}
+				carcassonne := playBots(t, []string{TeamA, TeamB}, bots, seed)
+				scores[seed] = map[string]int{"mcts": carcassonne.state.scores[mcts], "greedy": carcassonne.state.scores[greedy]}
+			})
+		}
+	})
+	mctsWins, mctsTotal, greedyTotal := 0, 0, 0
+	for _, score := range scores {
+		if score["mcts"] > score["greedy"] {
+			mctsWins++
+		}
+		mctsTotal += score["mcts"]
+		greedyTotal += score["greedy"]
+	}
+	assert.Greater(t, mctsWins, games/2, "mcts bot should win most games against greedy bot")
+	assert.Greater(t, float64(mctsTotal), 1.1*float64(greedyTotal), "mcts bot should outscore greedy bot by at least 10%")
+}
+
+func Test_MCTSBotDeterministic(t *testing.T) {
+	// bots with the same seed choose the same actions even when searching in parallel
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 7},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	first := NewMCTSBot(&MCTSBotOptions{Iterations: 100, Workers: 4, Seed: 7})
+	second := NewMCTSBot(&MCTSBotOptions{Iterations: 100, Workers: 4, Seed: 7})
+	for i := 0; i < 8 && len(carcassonne.state.winners) == 0; i++ {
+		team := carcassonne.state.turn
+		expected, err := first.Action(carcassonne, team)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		got, err := second.Action(carcassonne, team)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		assert.Equal(t, expected, got)
+		if err := carcassonne.Do(expected); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+}
+
+func Test_MCTSBotCancel(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams: []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{
+			Seed: 123,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	bot := NewMCTSBot(&MCTSBotOptions{Iterations: 1 << 30, Workers: 4, Seed: 123})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	action, err := bot.ActionContext(ctx, carcassonne, TeamA)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Less(t, time.Since(start), 5*time.Second, "search should stop once context is done")
+	assert.NoError(t, carcassonne.Do(action))
+}
@@ -0,0 +1,175 @@
+package go_carcassonne
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Languages bundled with the message catalog
+const (
+	English = "en"
+	Spanish = "es"
+)
+
+// Message keys
+const (
+	MessagePlaceTile     = "turn.placeTile"
+	MessagePlaceToken    = "turn.placeToken"
+	MessageWin           = "game.win"
+	MessagePartnersWin   = "game.partnersWin"
+	MessageTie           = "game.tie"
+	MessagePuzzleSolved  = "puzzle.solved"
+	MessagePuzzleFailed  = "puzzle.failed"
+	MessageSoloLost      = "solo.lost"
+	MessageEdgeMismatch  = "violation." + ReasonEdgeMismatch
+	MessageDisconnected  = "violation." + ReasonDisconnected
+	MessageOccupied      = "violation." + ReasonOccupied
+	MessageClaimed       = "violation." + ReasonClaimed
+	MessageNoTokens      = "violation." + ReasonNoTokens
+	MessageWrongPhase    = "violation." + ReasonWrongPhase
+	MessageNothingToDo   = "violation.NothingToDo"
+	MessageWrongTile     = "violation." + ReasonWrongTile
+	MessageWrongLocation = "violation." + ReasonWrongLocation
+)
+
+// termPrefix is the start of the keys translating game terms such as City or Top used as parameters
+const termPrefix = "term."
+
+// Message is a key in the message catalog with the parameters to fill in its translation
+type Message struct {
+	Key    string
+	Params map[string]string `json:",omitempty"`
+	// Terms are the names of the parameters holding game terms such as City or Top which are translated too
+	Terms []string `json:",omitempty"`
+}
+
+// Translations map message keys to templates where {Name} is replaced by the parameter Name
+// game terms are translated with the key term. followed by the term e.g. term.City
+type Translations map[string]string
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = map[string]Translations{
+		English: {
+			MessagePlaceTile:                   "{Team} must place a tile",
+			MessagePlaceToken:                  "{Team} must place a token",
+			MessageWin:                         "{Team} wins",
+			MessagePartnersWin:                 "{Team} win",
+			MessageTie:                         "{Teams} tie",
+			MessagePuzzleSolved:                "puzzle solved",
+			MessagePuzzleFailed:                "puzzle failed",
+			MessageSoloLost:                    "{Team} did not reach the target score of {Target}",
+			MessageEdgeMismatch:                "invalid tile placement as {Structure} on the {Side} side at {X},{Y} does not match {NeighborStructure} on the tile at {NeighborX},{NeighborY}",
+			MessageDisconnected:                "cannot add a disconnected tile to the board at {X},{Y}",
+			MessageOccupied:                    "tile already at {X},{Y}",
+			MessageClaimed:                     "cannot place token on {Structure} that is already claimed by {Owners}",
+			MessageNoTokens:                    "not enough tokens to place for team {Team}",
+			MessageWrongPhase:                  "{Team} must {Phase} not {Action}",
+			MessageNothingToDo:                 "{Team} cannot {Action}",
+			MessageWrongTile:                   "{Team} cannot place tile {Tile}",
+			MessageWrongLocation:               "cannot place token at {X},{Y} as only the tile at {TileX},{TileY} can have a token",
+			termPrefix + City:                  "city",
+			termPrefix + Road:                  "road",
+			termPrefix + Farm:                  "farm",
+			termPrefix + Cloister:              "cloister",
+			termPrefix + SideTop:               "top",
+			termPrefix + SideRight:             "right",
+			termPrefix + SideBottom:            "bottom",
+			termPrefix + SideLeft:              "left",
+			termPrefix + ActionPlaceTile:       "place a tile",
+			termPrefix + ActionPlaceToken:      "place a token",
+			termPrefix + ActionRotateTileRight: "rotate the tile right",
+			termPrefix + ActionRotateTileLeft:  "rotate the tile left",
+		},
+		Spanish: {
+			MessagePlaceTile:                   "{Team} debe colocar una loseta",
+			MessagePlaceToken:                  "{Team} debe colocar un seguidor",
+			MessageWin:                         "{Team} gana",
+			MessagePartnersWin:                 "{Team} ganan",
+			MessageTie:                         "empate entre {Teams}",
+			MessagePuzzleSolved:                "puzle resuelto",
+			MessagePuzzleFailed:                "puzle fallido",
+			MessageSoloLost:                    "{Team} no alcanzó la puntuación objetivo de {Target}",
+			MessageEdgeMismatch:                "colocación no válida: {Structure} en el lado {Side} en {X},{Y} no coincide con {NeighborStructure} de la loseta en {NeighborX},{NeighborY}",
+			MessageDisconnected:                "no se puede colocar una loseta sin conexión en {X},{Y}",
+			MessageOccupied:                    "ya hay una loseta en {X},{Y}",
+			MessageClaimed:                     "no se puede colocar un seguidor en {Structure} porque ya es de {Owners}",
+			MessageNoTokens:                    "{Team} no tiene seguidores para colocar",
+			MessageWrongPhase:                  "{Team} debe {Phase} y no {Action}",
+			MessageNothingToDo:                 "{Team} no puede {Action}",
+			MessageWrongTile:                   "{Team} no puede colocar la loseta {Tile}",
+			MessageWrongLocation:               "no se puede colocar un seguidor en {X},{Y} ya que solo la loseta en {TileX},{TileY} puede tenerlo",
+			termPrefix + City:                  "ciudad",
+			termPrefix + Road:                  "camino",
+			termPrefix + Farm:                  "campo",
+			termPrefix + Cloister:              "monasterio",
+			termPrefix + SideTop:               "superior",
+			termPrefix + SideRight:             "derecho",
+			termPrefix + SideBottom:            "inferior",
+			termPrefix + SideLeft:              "izquierdo",
+			termPrefix + ActionPlaceTile:       "colocar loseta",
+			termPrefix + ActionPlaceToken:      "colocar seguidor",
+			termPrefix + ActionRotateTileRight: "girar la loseta a la derecha",
+			termPrefix + ActionRotateTileLeft:  "girar la loseta a la izquierda",
+		},
+	}
+)
+
+// RegisterTranslations adds translations for language replacing any already registered for the same keys
+func RegisterTranslations(language string, translations Translations) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if catalog[language] == nil {
+		catalog[language] = make(Translations, len(translations))
+	}
+	for key, template := range translations {
+		catalog[language][key] = template
+	}
+}
+
+// Languages gets the languages with translations in alphabetical order
+func Languages() []string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	languages := make([]string, 0, len(catalog))
+	for language := range catalog {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+func newMessage(key string, params map[string]string, terms ...string) *Message {
+	return &Message{Key: key, Params: params, Terms: terms}
+}
+
+// Localize gets the message in language falling back to English for keys the language does not translate
+func (m *Message) Localize(language string) string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	replacements := make([]string, 0, 2*len(m.Params))
+	for name, value := range m.Params {
+		if contains(m.Terms, name) {
+			value = translate(language, termPrefix+value, value)
+		}
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(replacements...).Replace(translate(language, m.Key, m.Key))
+}
+
+// String gets the message in English
+func (m *Message) String() string {
+	return m.Localize(English)
+}
+
+// translate gets the template for key in language, then in English, and otherwise fallback
+func translate(language, key, fallback string) string {
+	if template, ok := catalog[language][key]; ok {
+		return template
+	}
+	if template, ok := catalog[English][key]; ok {
+		return template
+	}
+	return fallback
+}
@@ -0,0 +1,73 @@
+package go_carcassonne
+
+import (
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Message(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 4},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	snapshot, _ := carcassonne.GetSnapshot(TeamA)
+	message := snapshot.MoreData.(CarcassonneSnapshotData).Message
+	assert.Equal(t, &Message{Key: MessagePlaceTile, Params: map[string]string{"Team": TeamA}}, message)
+	assert.Equal(t, "TeamA must place a tile", snapshot.Message)
+	assert.Equal(t, "TeamA debe colocar una loseta", message.Localize(Spanish))
+
+	if err := carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: bg.ActionSetWinners, MoreDetails: bg.SetWinnersActionDetails{Winners: []string{TeamA, TeamB}}}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	snapshot, _ = carcassonne.GetSnapshot()
+	message = snapshot.MoreData.(CarcassonneSnapshotData).Message
+	assert.Equal(t, MessageTie, message.Key)
+	assert.Equal(t, "TeamA, TeamB tie", snapshot.Message)
+	assert.Equal(t, "empate entre TeamA, TeamB", message.Localize(Spanish))
+}
+
+func Test_Message_Violation(t *testing.T) {
+	violation := &EdgeMismatchError{X: 0, Y: 1, Side: SideBottom, Structure: Road, NeighborX: 0, NeighborY: 0, NeighborStructure: City}
+	assert.Equal(t, "invalid tile placement as road on the bottom side at 0,1 does not match city on the tile at 0,0", violation.Error())
+	assert.Equal(t, "colocación no válida: camino en el lado inferior en 0,1 no coincide con ciudad de la loseta en 0,0", violation.Message().Localize(Spanish))
+
+	claimed := &ClaimedError{X: 1, Y: 0, Side: SideTop, Structure: City, Owners: []string{TeamA, TeamB}}
+	assert.Equal(t, "cannot place token on city that is already claimed by TeamA, TeamB", claimed.Error())
+	assert.Equal(t, "no se puede colocar un seguidor en ciudad porque ya es de TeamA, TeamB", claimed.Message().Localize(Spanish))
+
+	// actions and phases are written as words rather than action types
+	phase := &WrongPhaseError{Team: TeamA, Action: ActionPlaceToken, Phase: ActionPlaceTile}
+	assert.Equal(t, "TeamA must place a tile not place a token", phase.Error())
+	assert.Equal(t, "TeamA debe colocar loseta y no colocar seguidor", phase.Message().Localize(Spanish))
+	rotate := &WrongPhaseError{Team: TeamA, Action: ActionRotateTileLeft, Phase: ActionPlaceToken}
+	assert.Equal(t, "TeamA must place a token not rotate the tile left", rotate.Error())
+	done := &WrongPhaseError{Team: TeamA, Action: ActionRotateTileRight}
+	assert.Equal(t, "TeamA cannot rotate the tile right", done.Error())
+	assert.Equal(t, "TeamA no puede girar la loseta a la derecha", done.Message().Localize(Spanish))
+}
+
+func Test_RegisterTranslations(t *testing.T) {
+	RegisterTranslations("fr", Translations{
+		MessagePlaceTile: "{Team} doit poser une tuile",
+	})
+	assert.Contains(t, Languages(), "fr")
+	assert.Contains(t, Languages(), English)
+	assert.Contains(t, Languages(), Spanish)
+
+	assert.Equal(t, "TeamA doit poser une tuile", newMessage(MessagePlaceTile, map[string]string{"Team": TeamA}).Localize("fr"))
+	// keys the language does not translate fall back to English
+	assert.Equal(t, "TeamA wins", newMessage(MessageWin, map[string]string{"Team": TeamA}).Localize("fr"))
+	assert.Equal(t, "TeamA wins", newMessage(MessageWin, map[string]string{"Team": TeamA}).Localize("de"))
+	// registering again replaces only the keys given
+	RegisterTranslations(Spanish, Translations{MessageWin: "¡{Team} gana!"})
+	defer RegisterTranslations(Spanish, Translations{MessageWin: "{Team} gana"})
+	assert.Equal(t, "¡TeamA gana!", newMessage(MessageWin, map[string]string{"Team": TeamA}).Localize(Spanish))
+	assert.Equal(t, "puzle resuelto", newMessage(MessagePuzzleSolved, nil).Localize(Spanish))
+}
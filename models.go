@@ -6,11 +6,65 @@ const (
 	ActionPlaceToken      = "PlaceToken"
 	ActionRotateTileRight = "RotateTileRight"
 	ActionRotateTileLeft  = "RotateTileLeft"
+	ActionResign          = "Resign"
+)
+
+// What happens to the tokens on the board of a team that resigns
+const (
+	// ResignReturn removes the tokens from the board so they no longer score or block other teams
+	ResignReturn = "Return"
+
+	// ResignFreeze leaves the tokens on the board where they still count when finding who scores a structure
+	ResignFreeze = "Freeze"
+)
+
+// Visibility of the tiles held by each team
+const (
+	// VisibilityPrivate shows each team only the tiles it holds
+	VisibilityPrivate = "Private"
+
+	// VisibilityPublic shows every team the tiles held by all teams
+	VisibilityPublic = "Public"
 )
 
 // CarcassonneMoreOptions are the additional options for creating a game of Carcassonne
 type CarcassonneMoreOptions struct {
 	Seed int64
+
+	// Deck is the tile types, indexes into the tile list, drawn first and in order before the rest of the shuffled deck
+	// used to replay games where the tiles drawn are already known
+	Deck []int
+
+	// Position is the position notation of the state to start the game from instead of a new game
+	Position string
+
+	// Goal is the goal of a puzzle played by a single team, see PuzzleGoalPoints and PuzzleGoalCity
+	Goal string
+
+	// Solo sets up a game played by a single team against a target score or the automa
+	Solo *SoloOptions
+
+	// Partnerships groups teams that share a combined score and win together e.g. [["Red", "Blue"], ["Green", "Yellow"]]
+	Partnerships [][]string
+
+	// SharedMajority counts the tokens of partners together when finding which teams score a structure
+	SharedMajority bool
+
+	// Clock times the game, nil for an untimed game
+	Clock *ClockOptions
+
+	// ResignTokens is what happens to the tokens of a team that resigns, ResignReturn if empty
+	ResignTokens string
+
+	// HandSize is the number of tiles each team holds and chooses from when placing a tile, 1 if 0
+	HandSize int
+
+	// Visibility is who sees the tiles held by each team, VisibilityPrivate if empty
+	// spectators i.e. snapshots without a team always see every tile held
+	Visibility string
+
+	// Reveal is the number of tiles at the top of the deck shown to everyone e.g. for teaching games
+	Reveal int
 }
 
 // PlaceTileActionDetails is the action details for placing a tile
@@ -20,6 +74,9 @@ type PlaceTileActionDetails struct {
 
 	// Tile is the tile being placed
 	Tile TileActionDetails
+
+	// Slot is the position in the team's hand of the tile being placed where 0 is the play tile
+	Slot int
 }
 
 type TileActionDetails struct {
@@ -45,12 +102,25 @@ type PlaceTokenActionDetails struct {
 // CarcassonneSnapshotData is the game data unique to Carcassonne
 type CarcassonneSnapshotData struct {
 	PlayTile        *tile
+	Hand            []*tile // the other tiles in the team's hand after the play tile
 	LastPlacedTiles map[string]*tile
 	Board           []*tile
 	BoardTokens     []*token
 	Tokens          map[string]int
 	Scores          map[string]int
+	FeatureScores   map[string]map[string]int
 	TilesRemaining  int
+	Puzzle          *PuzzleStatus // nil if not a puzzle
+	Solo            *SoloStatus   // nil if not a solo game
+	Partnerships    []*Partnership
+	Clock           *ClockStatus       // nil if the game is not timed
+	Resigned        []string           // teams that left the game
+	Hands           map[string][]*tile // tiles held by each team with the play tile first, only set when every hand can be seen
+	Revealed        []*tile            // tiles at the top of the deck in the order they will be drawn
+	RemainingTiles  []*RemainingTile   // tiles not yet drawn by type without their order
+	OpenSpaces      []*OpenSpace       // empty spaces with the number of remaining tiles that fit each
+	DeadSpaces      []*DeadSpace       // empty spaces no tile can ever fill
+	Message         *Message           // the snapshot message as a catalog key and parameters for localizing
 }
 
 // startTile the tile at 0,0 at the start of the game
@@ -61,6 +131,15 @@ type tileAmounts struct {
 	amount int
 }
 
+// totalTiles is the number of tiles in the deck at the start of the game
+var totalTiles = func() int {
+	total := 0
+	for _, tileAmount := range tiles {
+		total += tileAmount.amount
+	}
+	return total
+}()
+
 // tiles are all the tiles that will be placed
 var tiles = []*tileAmounts{
 	{tile: newTile(Farm, Farm, Farm, Farm, Cloister, false, false), amount: 4},
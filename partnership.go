@@ -0,0 +1,167 @@
+package go_carcassonne
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Partnership is a group of teams that share a combined score and win or lose together
+type Partnership struct {
+	Teams []string
+	Score int
+}
+
+// validatePartnerships checks that every team is in exactly one of at least two partnerships
+func validatePartnerships(teams []string, partnerships [][]string) error {
+	if len(partnerships) < 2 {
+		return fmt.Errorf("at least 2 partnerships required")
+	}
+	seen := make(map[string]bool)
+	for _, partnership := range partnerships {
+		if len(partnership) == 0 {
+			return fmt.Errorf("partnerships cannot be empty")
+		}
+		for _, team := range partnership {
+			if !contains(teams, team) {
+				return fmt.Errorf("%s is not a team in the game", team)
+			} else if seen[team] {
+				return fmt.Errorf("%s is in more than one partnership", team)
+			}
+			seen[team] = true
+		}
+	}
+	if len(seen) != len(teams) {
+		return fmt.Errorf("every team must be in a partnership")
+	}
+	return nil
+}
+
+// partnership gets the index of the partnership of team or -1 if there are no partnerships
+func (s *state) partnership(team string) int {
+	for i, partnership := range s.partnerships {
+		if contains(partnership, team) {
+			return i
+		}
+	}
+	return -1
+}
+
+// pointsWinners gets the teams awarded the points of a structure containing tokens
+// with shared majorities the tokens of partners count together and the partnership is awarded the points once
+// through the partner with the most tokens in the structure, the first in turn order on a tie
+func (s *state) pointsWinners(tokens []*token) []string {
+	if !s.sharedMajority {
+		return pointsWinners(tokens)
+	}
+	tally := make(map[int]int)
+	teamTally := make(map[string]int)
+	for _, token := range tokens {
+		tally[s.partnership(token.Team)]++
+		teamTally[token.Team]++
+	}
+	max := 0
+	for _, count := range tally {
+		if count > max {
+			max = count
+		}
+	}
+	winners := make([]string, 0)
+	for i, partnership := range s.partnerships {
+		if tally[i] == 0 || tally[i] != max {
+			continue
+		}
+		best := ""
+		for _, team := range s.teams {
+			if contains(partnership, team) && (best == "" || teamTally[team] > teamTally[best]) {
+				best = team
+			}
+		}
+		winners = append(winners, best)
+	}
+	sort.Strings(winners)
+	return winners
+}
+
+// highestScorers gets the teams with the highest score or every team in the partnerships with the highest combined score
+// excluded teams are never included but still add to the combined score of their partnership
+func (s *state) highestScorers(excluded ...string) []string {
+	max := 0
+	winners := make([]string, 0)
+	if len(s.partnerships) == 0 {
+		for _, team := range s.teams {
+			if contains(excluded, team) {
+				continue
+			}
+			score := s.scores[team]
+			if score > max {
+				max = score
+				winners = []string{team}
+			} else if score == max {
+				winners = append(winners, team)
+			}
+		}
+		return winners
+	}
+	for _, partnership := range s.partnershipScores() {
+		teams := make([]string, 0, len(partnership.Teams))
+		for _, team := range partnership.Teams {
+			if !contains(excluded, team) {
+				teams = append(teams, team)
+			}
+		}
+		if len(teams) == 0 {
+			continue
+		}
+		if partnership.Score > max {
+			max = partnership.Score
+			winners = teams
+		} else if partnership.Score == max {
+			winners = append(winners, teams...)
+		}
+	}
+	return winners
+}
+
+// partnershipScores gets each partnership with its combined score
+func (s *state) partnershipScores() []*Partnership {
+	partnerships := make([]*Partnership, 0, len(s.partnerships))
+	for _, teams := range s.partnerships {
+		partnership := &Partnership{Teams: teams}
+		for _, team := range teams {
+			partnership.Score += s.scores[team]
+		}
+		partnerships = append(partnerships, partnership)
+	}
+	return partnerships
+}
+
+// encodePartnerships writes the Partnerships tag as the team indexes of each partnership e.g. 0.2/1.3
+func encodePartnerships(teams []string, partnerships [][]string) string {
+	notation := make([]string, 0, len(partnerships))
+	for _, partnership := range partnerships {
+		indexes := make([]string, 0, len(partnership))
+		for _, team := range partnership {
+			indexes = append(indexes, strconv.Itoa(indexOf(teams, team)))
+		}
+		notation = append(notation, strings.Join(indexes, "."))
+	}
+	return strings.Join(notation, "/")
+}
+
+func decodePartnerships(teams []string, notation string) ([][]string, error) {
+	partnerships := make([][]string, 0)
+	for _, field := range strings.Split(notation, "/") {
+		partnership := make([]string, 0)
+		for _, index := range strings.Split(field, ".") {
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 || i >= len(teams) {
+				return nil, loadFailure(fmt.Errorf("got %s but wanted a team index from 0 to %d", index, len(teams)-1))
+			}
+			partnership = append(partnership, teams[i])
+		}
+		partnerships = append(partnerships, partnership)
+	}
+	return partnerships, nil
+}
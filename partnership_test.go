@@ -0,0 +1,102 @@
+package go_carcassonne
+
+import (
+	"math/rand"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Partnership_PointsWinners(t *testing.T) {
+	teams := []string{TeamA, TeamB, "TeamC", "TeamD"}
+	s := newState(teams, 0, nil)
+	s.partnerships = [][]string{{TeamA, "TeamC"}, {TeamB, "TeamD"}}
+	tests := []struct {
+		name     string
+		tokens   []string
+		shared   bool
+		expected []string
+	}{
+		{name: "partners outnumber", tokens: []string{TeamA, "TeamC", TeamB}, shared: true, expected: []string{TeamA}},
+		{name: "partners outnumber without sharing", tokens: []string{TeamA, "TeamC", TeamB}, expected: []string{TeamA, TeamB, "TeamC"}},
+		{name: "partner with most tokens scores", tokens: []string{"TeamC", TeamA, "TeamC", TeamB, "TeamD"}, shared: true, expected: []string{"TeamC"}},
+		{name: "partnerships tie", tokens: []string{"TeamC", TeamA, TeamB, "TeamD"}, shared: true, expected: []string{TeamA, TeamB}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.sharedMajority = test.shared
+			tokens := make([]*token, 0)
+			for _, team := range test.tokens {
+				tokens = append(tokens, newToken(0, 0, team, Knight, SideTop))
+			}
+			assert.Equal(t, test.expected, s.pointsWinners(tokens))
+		})
+	}
+}
+
+func Test_Partnership(t *testing.T) {
+	teams := []string{TeamA, TeamB, "TeamC", "TeamD"}
+	partnerships := [][]string{{TeamA, "TeamC"}, {TeamB, "TeamD"}}
+	for _, seed := range []int64{1, 2, 3} {
+		carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+			Teams:       teams,
+			MoreOptions: CarcassonneMoreOptions{Seed: seed, Partnerships: partnerships, SharedMajority: true},
+		})
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		random := rand.New(rand.NewSource(seed))
+		for len(carcassonne.state.winners) == 0 {
+			if err := carcassonne.Do(randomAction(carcassonne.state, random)); err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+		}
+		snapshot, err := carcassonne.GetSnapshot()
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		data := snapshot.MoreData.(CarcassonneSnapshotData)
+		assert.Len(t, data.Partnerships, 2)
+		for _, partnership := range data.Partnerships {
+			assert.Equal(t, carcassonne.state.scores[partnership.Teams[0]]+carcassonne.state.scores[partnership.Teams[1]], partnership.Score)
+		}
+		// partners win together
+		assert.Contains(t, snapshot.Message, " & ")
+		for _, partnership := range partnerships {
+			assert.Equal(t, contains(snapshot.Winners, partnership[0]), contains(snapshot.Winners, partnership[1]))
+		}
+
+		game := carcassonne.GetBGN()
+		assert.Equal(t, "0.2/1.3", game.Tags["Partnerships"])
+		assert.Equal(t, "true", game.Tags["SharedMajority"])
+		builder := Builder{}
+		loaded, err := builder.Load(game)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		assert.Equal(t, partnerships, loaded.(*Carcassonne).state.partnerships)
+		assert.Equal(t, carcassonne.state.scores, loaded.(*Carcassonne).state.scores)
+		assert.Equal(t, carcassonne.state.winners, loaded.(*Carcassonne).state.winners)
+	}
+}
+
+func Test_Partnership_Invalid(t *testing.T) {
+	teams := []string{TeamA, TeamB, "TeamC", "TeamD"}
+	tests := []CarcassonneMoreOptions{
+		{Partnerships: [][]string{{TeamA, TeamB, "TeamC", "TeamD"}}},
+		{Partnerships: [][]string{{TeamA, "TeamC"}, {TeamB}}},
+		{Partnerships: [][]string{{TeamA, "TeamC"}, {TeamB, "TeamD", TeamA}}},
+		{Partnerships: [][]string{{TeamA, "TeamC"}, {TeamB, "TeamE"}}},
+		{Partnerships: [][]string{{TeamA, "TeamC"}, {}, {TeamB, "TeamD"}}},
+		{SharedMajority: true},
+	}
+	for _, test := range tests {
+		_, err := NewCarcassonne(&bg.BoardGameOptions{Teams: teams, MoreOptions: test})
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,449 @@
+package go_carcassonne
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+   Position notation describes a game at the start of a turn or before placing a token
+   as fields separated by spaces where lists are separated by / and - is an empty value
+
+     tiles        x.y.type.rotation of each placed tile in the order placed
+     tokens       team.x.y.token.side of each token on the board where side is left out for monks
+     scores       points of each team
+     supplies     tokens each team can still place
+     turn         index of the team whose turn it is
+     play tiles   type.rotation of the tile held by each team followed by +type.rotation of any other tiles in its hand
+     last placed  x.y of the tile last placed by each team
+     deck         types of the tiles left to draw in the order drawn
+
+   e.g. 0.0.19.0/1.0.21.0 0.1.0.t.b 0/0 6/7 1 0.0/3.0 1.0/- 23/5
+
+   teams are given by index so the position can be used with any team names
+   type is the index of the tile in the tile list and rotation the number of right rotations
+   completed structures are not colored by the teams that won them and scores by structure type start at 0
+*/
+
+// positionFields is the number of fields in position notation
+const positionFields = 8
+
+// Position gets the position notation of the current state of the game
+func (c *Carcassonne) Position() string {
+	return encodePosition(c.state)
+}
+
+func encodePosition(s *state) string {
+	tiles := make([]string, 0, len(s.board.board))
+	for _, t := range s.board.board {
+		typ, rotation, _ := tileType(tileToActionDetails(t))
+		tiles = append(tiles, positionJoin(".", t.X, t.Y, typ, rotation))
+	}
+	tokens := make([]string, 0, len(s.boardTokens))
+	for _, token := range s.boardTokens {
+		notation := []string{strconv.Itoa(indexOf(s.teams, token.Team)), strconv.Itoa(token.X), strconv.Itoa(token.Y), tokenToNotation[token.Type]}
+		switch token.Type {
+		case Knight, Thief:
+			notation = append(notation, sideToNotation[token.Side])
+		case Farmer:
+			notation = append(notation, farmSideToNotation[token.Side])
+		}
+		tokens = append(tokens, strings.Join(notation, "."))
+	}
+	scores := make([]string, 0, len(s.teams))
+	supplies := make([]string, 0, len(s.teams))
+	playTiles := make([]string, 0, len(s.teams))
+	lastPlacedTiles := make([]string, 0, len(s.teams))
+	for _, team := range s.teams {
+		scores = append(scores, strconv.Itoa(s.scores[team]))
+		supplies = append(supplies, strconv.Itoa(s.tokens[team]))
+		playTile := "-"
+		if t := s.playTiles[team]; t != nil {
+			typ, rotation, _ := tileType(tileToActionDetails(t))
+			playTile = positionJoin(".", typ, rotation)
+		}
+		for _, t := range s.hands[team] {
+			typ, rotation, _ := tileType(tileToActionDetails(t))
+			playTile += "+" + positionJoin(".", typ, rotation)
+		}
+		playTiles = append(playTiles, playTile)
+		lastPlacedTile := "-"
+		if t := s.lastPlacedTiles[team]; t != nil {
+			lastPlacedTile = positionJoin(".", t.X, t.Y)
+		}
+		lastPlacedTiles = append(lastPlacedTiles, lastPlacedTile)
+	}
+	// tiles are drawn from the end of the deck
+	deck := make([]string, 0, s.deck.Size())
+	for i := s.deck.Size() - 1; i >= 0; i-- {
+		typ, _, _ := tileType(tileToActionDetails(s.deck.tiles[i]))
+		deck = append(deck, strconv.Itoa(typ))
+	}
+	return strings.Join([]string{
+		positionList(tiles),
+		positionList(tokens),
+		positionList(scores),
+		positionList(supplies),
+		strconv.Itoa(indexOf(s.teams, s.turn)),
+		positionList(playTiles),
+		positionList(lastPlacedTiles),
+		positionList(deck),
+	}, " ")
+}
+
+// decodePosition creates the state described by the position notation where seed is used for any later shuffles
+func decodePosition(teams []string, seed int64, notation string) (*state, error) {
+	fields := strings.Fields(notation)
+	if len(fields) != positionFields {
+		return nil, fmt.Errorf("got %d but wanted %d fields in position", len(fields), positionFields)
+	}
+	s := newState(teams, seed, nil)
+	s.board = &board{board: make([]*tile, 0)}
+
+	// tiles
+	for _, field := range positionSplit(fields[0]) {
+		values, err := positionInts(field, 4)
+		if err != nil {
+			return nil, fmt.Errorf("tile %s: %w", field, err)
+		}
+		t, err := positionTile(values[2], values[3])
+		if err != nil {
+			return nil, fmt.Errorf("tile %s: %w", field, err)
+		}
+		if len(s.board.board) == 0 {
+			t.X, t.Y = values[0], values[1]
+			s.board.board = append(s.board.board, t)
+		} else if err := s.board.Place(t, values[0], values[1]); err != nil {
+			return nil, fmt.Errorf("tile %s: %w", field, err)
+		}
+	}
+	if len(s.board.board) == 0 {
+		return nil, fmt.Errorf("position requires at least one tile")
+	}
+	// the game has one more tile of the start tile's type so the first tile of that type is taken as the start tile
+	start, _, _ := tileType(tileToActionDetails(startTile))
+	for _, t := range s.board.board {
+		if typ, _, _ := tileType(tileToActionDetails(t)); typ == start {
+			s.board.start = t
+			break
+		}
+	}
+
+	// tokens
+	for _, field := range positionSplit(fields[1]) {
+		token, err := decodePositionToken(s, field)
+		if err != nil {
+			return nil, fmt.Errorf("token %s: %w", field, err)
+		}
+		s.boardTokens = append(s.boardTokens, token)
+	}
+
+	// scores and supplies
+	scores, err := positionTeamInts(fields[2], len(teams))
+	if err != nil {
+		return nil, fmt.Errorf("scores: %w", err)
+	}
+	supplies, err := positionTeamInts(fields[3], len(teams))
+	if err != nil {
+		return nil, fmt.Errorf("supplies: %w", err)
+	}
+	for i, team := range teams {
+		if scores[i] < 0 || supplies[i] < 0 {
+			return nil, fmt.Errorf("scores and supplies cannot be negative")
+		}
+		s.scores[team] = scores[i]
+		s.tokens[team] = supplies[i]
+	}
+	if err := validatePositionTokens(s); err != nil {
+		return nil, err
+	}
+
+	// turn
+	turn, err := strconv.Atoi(fields[4])
+	if err != nil || turn < 0 || turn >= len(teams) {
+		return nil, fmt.Errorf("got turn %s but wanted a team index from 0 to %d", fields[4], len(teams)-1)
+	}
+	s.turn = teams[turn]
+
+	// play tiles and last placed tiles
+	playTiles := strings.Split(fields[5], "/")
+	lastPlacedTiles := strings.Split(fields[6], "/")
+	if len(playTiles) != len(teams) || len(lastPlacedTiles) != len(teams) {
+		return nil, fmt.Errorf("play tiles and last placed tiles are required for each of the %d teams", len(teams))
+	}
+	for i, team := range teams {
+		s.playTiles[team] = nil
+		s.hands[team] = make([]*tile, 0)
+		for j, notation := range strings.Split(playTiles[i], "+") {
+			if j == 0 && notation == "-" {
+				continue
+			}
+			values, err := positionInts(notation, 2)
+			if err != nil {
+				return nil, fmt.Errorf("play tile %s: %w", notation, err)
+			}
+			t, err := positionTile(values[0], values[1])
+			if err != nil {
+				return nil, fmt.Errorf("play tile %s: %w", notation, err)
+			}
+			if j == 0 {
+				s.playTiles[team] = t
+			} else {
+				s.hands[team] = append(s.hands[team], t)
+			}
+		}
+		s.lastPlacedTiles[team] = nil
+		if lastPlacedTiles[i] != "-" {
+			values, err := positionInts(lastPlacedTiles[i], 2)
+			if err != nil {
+				return nil, fmt.Errorf("last placed tile %s: %w", lastPlacedTiles[i], err)
+			}
+			if s.lastPlacedTiles[team] = s.board.tile(values[0], values[1]); s.lastPlacedTiles[team] == nil {
+				return nil, fmt.Errorf("last placed tile %s is not on the board", lastPlacedTiles[i])
+			}
+		}
+	}
+	// the hands are as large as the largest hand given where a team placing a token has already played one tile of it
+	for _, team := range teams {
+		s.handSize = max(s.handSize, len(s.hands[team])+1)
+	}
+	if s.playTiles[s.turn] == nil && s.lastPlacedTiles[s.turn] == nil {
+		return nil, fmt.Errorf("team %d requires a play tile or a last placed tile to place a token on", turn)
+	}
+	// cities completed by a tile whose token is still to be placed are added once the token is placed
+	var pending *tile
+	if s.playTiles[s.turn] == nil {
+		pending = s.lastPlacedTiles[s.turn]
+	}
+	if err := s.board.findCompleteCities(pending); err != nil {
+		return nil, err
+	}
+
+	// deck
+	s.deck.tiles = make([]*tile, 0)
+	order := positionSplit(fields[7])
+	for i := len(order) - 1; i >= 0; i-- {
+		typ, err := strconv.Atoi(order[i])
+		if err != nil || typ < 0 || typ >= len(tiles) {
+			return nil, fmt.Errorf("got deck tile %s but wanted a tile type from 0 to %d", order[i], len(tiles)-1)
+		}
+		s.deck.tiles = append(s.deck.tiles, tiles[typ].tile.copy())
+	}
+	if err := validatePositionTiles(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// validatePositionTiles checks the board, hands, and deck use no more of each tile type than the game has
+func validatePositionTiles(s *state) error {
+	used := make([]int, len(tiles))
+	all := s.board.placedTiles()
+	for _, team := range s.teams {
+		all = append(all, s.hand(team)...)
+	}
+	all = append(all, s.deck.tiles...)
+	for _, t := range all {
+		typ, _, _ := tileType(tileToActionDetails(t))
+		used[typ]++
+	}
+	for typ, tileAmount := range tiles {
+		if used[typ] > tileAmount.amount {
+			return fmt.Errorf("tile type %d is used %d times but the game only has %d", typ, used[typ], tileAmount.amount)
+		}
+	}
+	return nil
+}
+
+// validatePositionTokens checks each team has no more tokens than it started with
+// and that every token could have been placed i.e. it is the only token on its tile
+// and its structure was unclaimed when its tile was placed as structures only join up as later tiles are placed
+func validatePositionTokens(s *state) error {
+	for _, team := range s.teams {
+		onBoard := 0
+		for _, token := range s.boardTokens {
+			if token.Team == team {
+				onBoard++
+			}
+		}
+		if onBoard+s.tokens[team] > teamTokens {
+			return fmt.Errorf("team %d has %d tokens on the board and %d in supply but only %d tokens", indexOf(s.teams, team), onBoard, s.tokens[team], teamTokens)
+		}
+	}
+	// place the tiles again in order checking the tokens on each tile as it is placed
+	b := &board{board: make([]*tile, 0)}
+	placed := make([]*token, 0)
+	for _, t := range s.board.board {
+		copied := t.copy()
+		if len(b.board) == 0 {
+			copied.X, copied.Y = t.X, t.Y
+			b.board = append(b.board, copied)
+		} else if err := b.Place(copied, t.X, t.Y); err != nil {
+			return err
+		}
+		onTile := 0
+		for _, token := range s.boardTokens {
+			if token.X != t.X || token.Y != t.Y {
+				continue
+			}
+			if onTile++; onTile > 1 {
+				return fmt.Errorf("tile %d,%d has more than one token", t.X, t.Y)
+			}
+			var found *structure
+			var err error
+			switch token.Type {
+			case Knight:
+				found, err = b.generateCity(t.X, t.Y, token.Side)
+			case Thief:
+				found, err = b.generateRoad(t.X, t.Y, token.Side)
+			case Farmer:
+				found, err = b.generateFarm(t.X, t.Y, token.Side)
+			}
+			if err != nil {
+				return err
+			}
+			if found != nil && len(tokensInStructure(placed, found)) > 0 {
+				return fmt.Errorf("token %s at %d,%d is on a structure that was already claimed", strings.ToLower(token.Type), t.X, t.Y)
+			}
+			placed = append(placed, token)
+		}
+	}
+	return nil
+}
+
+func decodePositionToken(s *state, notation string) (*token, error) {
+	fields := strings.Split(notation, ".")
+	if len(fields) != 4 && len(fields) != 5 {
+		return nil, fmt.Errorf("got %d but wanted 4 or 5 fields", len(fields))
+	}
+	values, err := positionInts(strings.Join(fields[:3], "."), 3)
+	if err != nil {
+		return nil, err
+	}
+	if values[0] < 0 || values[0] >= len(s.teams) {
+		return nil, fmt.Errorf("got team %d but wanted a team index from 0 to %d", values[0], len(s.teams)-1)
+	}
+	t := s.board.tile(values[1], values[2])
+	if t == nil {
+		return nil, fmt.Errorf("no tile at %d,%d", values[1], values[2])
+	}
+	typ, ok := notationToToken[fields[3]]
+	if !ok {
+		return nil, fmt.Errorf("invalid token %s", fields[3])
+	}
+	side := ""
+	switch {
+	case typ == Monk && len(fields) == 4:
+		if t.Center != Cloister {
+			return nil, fmt.Errorf("no cloister at %d,%d", t.X, t.Y)
+		}
+	case (typ == Knight || typ == Thief) && len(fields) == 5:
+		side = notationToSide[fields[4]]
+		if side == "" || t.Sides[side] != tokenTypeToStructureType[typ] {
+			return nil, fmt.Errorf("no %s at side %s", strings.ToLower(tokenTypeToStructureType[typ]), fields[4])
+		}
+	case typ == Farmer && len(fields) == 5:
+		side = notationToFarmSide[fields[4]]
+		if side == "" || t.Sides[farmSideToSide(side)] == City {
+			return nil, fmt.Errorf("no farm at side %s", fields[4])
+		}
+	default:
+		return nil, fmt.Errorf("invalid side for %s", strings.ToLower(typ))
+	}
+	return newToken(t.X, t.Y, s.teams[values[0]], typ, side), nil
+}
+
+// findCompleteCities adds every complete city on the board that does not include pending to the complete structures
+// complete roads are only added when scored like during a game so are left out as a position does not record which were scored
+func (b *board) findCompleteCities(pending *tile) error {
+	b.completeCities = make([]*structure, 0)
+	b.completeRoads = make([]*structure, 0)
+	seen := make(map[*tile]map[string]bool)
+	for _, t := range b.board {
+		for _, side := range Sides {
+			if t.Sides[side] != City || seen[t][side] {
+				continue
+			}
+			found, err := b.generateCity(t.X, t.Y, side)
+			if err != nil {
+				return err
+			}
+			includesPending := false
+			for _, n := range found.nodes {
+				if seen[n.tile] == nil {
+					seen[n.tile] = make(map[string]bool)
+				}
+				for _, s := range n.sides {
+					seen[n.tile][s] = true
+				}
+				includesPending = includesPending || n.tile == pending
+			}
+			if found.complete && !includesPending {
+				b.completeCities = append(b.completeCities, found)
+			}
+		}
+	}
+	return nil
+}
+
+// positionTile creates a tile of the type rotated right rotation times
+func positionTile(typ, rotation int) (*tile, error) {
+	if typ < 0 || typ >= len(tiles) {
+		return nil, fmt.Errorf("got %d but wanted a tile type from 0 to %d", typ, len(tiles)-1)
+	}
+	if rotation < 0 || rotation > 3 {
+		return nil, fmt.Errorf("got %d but wanted a rotation from 0 to 3", rotation)
+	}
+	t := tiles[typ].tile.copy()
+	for i := 0; i < rotation; i++ {
+		t.RotateRight()
+	}
+	return t, nil
+}
+
+func positionInts(notation string, size int) ([]int, error) {
+	fields := strings.Split(notation, ".")
+	if len(fields) != size {
+		return nil, fmt.Errorf("got %d but wanted %d fields", len(fields), size)
+	}
+	values := make([]int, 0, size)
+	for _, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func positionTeamInts(notation string, teams int) ([]int, error) {
+	fields := strings.Split(notation, "/")
+	if len(fields) != teams {
+		return nil, fmt.Errorf("got %d but wanted %d values", len(fields), teams)
+	}
+	return positionInts(strings.Join(fields, "."), teams)
+}
+
+func positionJoin(sep string, values ...int) string {
+	fields := make([]string, 0, len(values))
+	for _, value := range values {
+		fields = append(fields, strconv.Itoa(value))
+	}
+	return strings.Join(fields, sep)
+}
+
+func positionList(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	return strings.Join(items, "/")
+}
+
+func positionSplit(notation string) []string {
+	if notation == "-" {
+		return nil
+	}
+	return strings.Split(notation, "/")
+}
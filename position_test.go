@@ -0,0 +1,198 @@
+package go_carcassonne
+
+import (
+	"math/rand"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Position(t *testing.T) {
+	teams := []string{TeamA, TeamB, "TeamC"}
+	for _, actions := range []int{0, 1, 2, 25, 50, 1000} {
+		carcassonne := playRandom(t, teams, 11, actions)
+		position := carcassonne.Position()
+		loaded, err := NewCarcassonne(&bg.BoardGameOptions{
+			Teams:       teams,
+			MoreOptions: CarcassonneMoreOptions{Seed: 11, Position: position},
+		})
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		assert.Equal(t, position, loaded.Position())
+		assert.Equal(t, carcassonne.state.turn, loaded.state.turn)
+		assert.Equal(t, len(carcassonne.state.board.completeCities), len(loaded.state.board.completeCities))
+		if len(carcassonne.state.winners) == 0 {
+			assert.Equal(t, carcassonne.state.targets(), loaded.state.targets())
+			expected, err := carcassonne.state.projectedScores()
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			got, err := loaded.state.projectedScores()
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			assert.Equal(t, expected, got)
+		}
+	}
+}
+
+func Test_Position_Farm(t *testing.T) {
+	// a farmer beside the start tile city which is completed by the tile above it
+	builder := Builder{}
+	game, err := builder.CreateWithBGN(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Position: "0.0.19.0/0.1.15.2 0.0.0.f.ra 4/0 6/7 1 21.0/21.0 -/0.1 -"},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	carcassonne := game.(*Carcassonne)
+	assert.Equal(t, 4, carcassonne.state.scores[TeamA])
+	assert.Equal(t, 6, carcassonne.state.tokens[TeamA])
+	assert.Equal(t, TeamB, carcassonne.state.turn)
+	assert.Len(t, carcassonne.state.board.completeCities, 1)
+	scores, err := carcassonne.state.projectedScores()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, 7, scores[TeamA])
+
+	// games started from a position are saved with it
+	loaded, err := builder.Load(game.GetBGN())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, carcassonne.Position(), loaded.(*Carcassonne).Position())
+}
+
+func Test_Position_Invalid(t *testing.T) {
+	positions := []string{
+		"0.0.19.0 - 0/0 7/7 0 21.0/21.0 -/-",
+		"0.0.19.0/5.5.0.0 - 0/0 7/7 0 21.0/21.0 -/- -",
+		"0.0.19.0 0.0.0.k.r 0/0 7/7 0 21.0/21.0 -/- -",
+		"0.0.19.0 0.0.0.m 0/0 7/7 0 21.0/21.0 -/- -",
+		"0.0.19.0 - 0/0/0 7/7 0 21.0/21.0 -/- -",
+		"0.0.19.0 - 0/0 7/7 2 21.0/21.0 -/- -",
+		"0.0.19.0 - 0/0 7/7 0 -/21.0 -/- -",
+		"0.0.19.0 - 0/0 7/7 0 24.0/21.0 -/- -",
+		"0.0.19.0 - 0/0 7/7 0 21.0/21.0 -/- 24",
+		// there is only one tile of type 23
+		"0.0.19.0/1.0.23.0 - 0/0 7/7 0 21.0/21.0 -/- 23",
+		// TeamA has more than its 7 tokens
+		"0.0.19.0 0.0.0.t.r 0/0 7/7 0 21.0/21.0 -/- -",
+		// a tile only ever gets one token
+		"0.0.19.0 0.0.0.t.r/1.0.0.k.t 0/0 6/6 0 21.0/21.0 -/- -",
+		// the road was claimed by TeamA before the tile TeamB placed its thief on
+		"0.0.19.0/1.0.1.1 0.0.0.t.r/1.1.0.t.l 0/0 6/6 0 21.0/21.0 -/- -",
+	}
+	for _, position := range positions {
+		_, err := NewCarcassonne(&bg.BoardGameOptions{
+			Teams:       []string{TeamA, TeamB},
+			MoreOptions: CarcassonneMoreOptions{Position: position},
+		})
+		assert.Error(t, err, position)
+	}
+}
+
+func Test_Position_CompleteStructures(t *testing.T) {
+	// the road between the two cloisters is complete but only scored roads are recorded
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Position: "0.0.19.0/1.0.1.1/-1.0.1.3 - 0/0 7/7 0 21.0/21.0 -/- -"},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Empty(t, carcassonne.state.board.completeRoads)
+
+	// the city completed by the tile TeamA still has to place a token on is added once the token is placed
+	carcassonne, err = NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Position: "0.0.19.0/0.1.15.2 - 0/0 7/7 0 -/21.0 0.1/- -"},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Empty(t, carcassonne.state.board.completeCities)
+	if err := carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceToken, MoreDetails: PlaceTokenActionDetails{Pass: true}}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Len(t, carcassonne.state.board.completeCities, 1)
+}
+
+func Test_Position_StartTile(t *testing.T) {
+	// the start tile is found by its type rather than by being placed first
+	for _, test := range []struct {
+		position string
+		placed   int
+	}{
+		{position: "0.0.19.0/1.0.1.1 - 0/0 7/7 0 21.0/21.0 -/- -", placed: 1},
+		{position: "0.0.1.1/-1.0.19.0 - 0/0 7/7 0 21.0/21.0 -/- -", placed: 1},
+		{position: "0.0.1.1 - 0/0 7/7 0 21.0/21.0 -/- -", placed: 1},
+	} {
+		carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+			Teams:       []string{TeamA, TeamB},
+			MoreOptions: CarcassonneMoreOptions{Position: test.position},
+		})
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		placed := carcassonne.state.board.placedTiles()
+		assert.Len(t, placed, test.placed, test.position)
+		typ, _, _ := tileType(tileToActionDetails(placed[0]))
+		assert.Equal(t, 1, typ, test.position)
+	}
+}
+
+func Test_Position_HandSize(t *testing.T) {
+	// the hand size comes from the hands in the position when it is not given
+	teams := []string{TeamA, TeamB}
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       teams,
+		MoreOptions: CarcassonneMoreOptions{Seed: 4, HandSize: 3},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	random := rand.New(rand.NewSource(4))
+	for i := 0; i < 3; i++ {
+		if err := carcassonne.Do(randomAction(carcassonne.state, random)); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	loaded, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       teams,
+		MoreOptions: CarcassonneMoreOptions{Seed: 4, Position: carcassonne.Position()},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, 3, loaded.state.handSize)
+	for i := 0; i < 10; i++ {
+		action := randomAction(loaded.state, random)
+		if err := loaded.Do(action); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		for _, team := range teams {
+			if team != loaded.state.turn {
+				assert.Len(t, loaded.state.hand(team), 3)
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package go_carcassonne
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+)
+
+// Puzzle goal types
+const (
+	// PuzzleGoalPoints is scoring at least some points e.g. points.10.3 to score 10 points in 3 turns
+	PuzzleGoalPoints = "points"
+
+	// PuzzleGoalCity is completing the city on a side of a tile e.g. city.0.0.t.2 to complete the city on top of the tile at 0,0 in 2 turns
+	PuzzleGoalCity = "city"
+)
+
+// maxPuzzleSearch is the number of actions tried by SolvePuzzle before giving up
+const maxPuzzleSearch = 1000000
+
+/*
+   A puzzle is a single team game with a goal and is defined in BGN with no actions e.g.
+
+     [Game "Carcassonne"]
+     [Teams "Solver"]
+     [Seed "0"]
+     [Name "Close the city"]
+     [Position "0.0.19.0 - 0 7 0 15.2 - 21"]
+     [Goal "city.0.0.t.1"]
+
+   where the position sets up the board and the tiles that will be drawn and the goal is
+   the goal type followed by its details and the turns allowed where 0 allows any number of turns
+*/
+
+// PuzzleStatus is the progress towards the goal of a puzzle
+type PuzzleStatus struct {
+	Goal      string
+	TurnsLeft int // -1 if any number of turns are allowed
+	Solved    bool
+	Failed    bool
+}
+
+// puzzle tracks the goal of a single team game
+type puzzle struct {
+	typ        string
+	points     int
+	x, y       int
+	side       string
+	turns      int
+	notation   string
+	startScore int
+	placed     int
+	solved     bool
+	failed     bool
+}
+
+func newPuzzle(notation string, s *state) (*puzzle, error) {
+	fields := strings.Split(notation, ".")
+	p := &puzzle{typ: fields[0], notation: notation, startScore: s.scores[s.teams[0]]}
+	var err error
+	switch p.typ {
+	case PuzzleGoalPoints:
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("got %d but wanted 3 fields in %s goal", len(fields), p.typ)
+		}
+		if p.points, err = strconv.Atoi(fields[1]); err != nil || p.points <= 0 {
+			return nil, fmt.Errorf("got %s but wanted a positive number of points", fields[1])
+		}
+	case PuzzleGoalCity:
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("got %d but wanted 5 fields in %s goal", len(fields), p.typ)
+		}
+		values, err := positionInts(strings.Join(fields[1:3], "."), 2)
+		if err != nil {
+			return nil, err
+		}
+		p.x, p.y, p.side = values[0], values[1], notationToSide[fields[3]]
+		if _, err := s.board.generateCity(p.x, p.y, p.side); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("got goal %s but wanted %s or %s", p.typ, PuzzleGoalPoints, PuzzleGoalCity)
+	}
+	if p.turns, err = strconv.Atoi(fields[len(fields)-1]); err != nil || p.turns < 0 {
+		return nil, fmt.Errorf("got %s but wanted a number of turns of at least 0", fields[len(fields)-1])
+	}
+	return p, nil
+}
+
+// update checks the goal after an action where the puzzle fails once the turns are used or the game ends without reaching the goal
+func (p *puzzle) update(s *state, action *bg.BoardGameAction) error {
+	team := s.teams[0]
+	if action.ActionType == ActionPlaceTile {
+		p.placed++
+	}
+	switch p.typ {
+	case PuzzleGoalPoints:
+		p.solved = s.scores[team]-p.startScore >= p.points
+	case PuzzleGoalCity:
+		city, err := s.board.generateCity(p.x, p.y, p.side)
+		if err != nil {
+			return err
+		}
+		p.solved = city.complete
+	}
+	turnOver := s.playTiles[team] != nil || len(s.winners) > 0
+	if p.solved {
+		s.winners = []string{team}
+	} else if len(s.winners) > 0 || contains(s.resigned, team) || (p.turns > 0 && p.placed >= p.turns && turnOver) {
+		p.failed = true
+		s.winners = make([]string, 0)
+	}
+	return nil
+}
+
+func (p *puzzle) over() bool {
+	return p.solved || p.failed
+}
+
+func (p *puzzle) status() *PuzzleStatus {
+	turnsLeft := -1
+	if p.turns > 0 {
+		turnsLeft = p.turns - p.placed
+	}
+	return &PuzzleStatus{
+		Goal:      p.notation,
+		TurnsLeft: turnsLeft,
+		Solved:    p.solved,
+		Failed:    p.failed,
+	}
+}
+
+func (p *puzzle) message() *Message {
+	if p.solved {
+		return newMessage(MessagePuzzleSolved, nil)
+	}
+	return newMessage(MessagePuzzleFailed, nil)
+}
+
+// SolvePuzzle finds the actions that solve a puzzle game from its current state by trying the legal moves of each turn
+func SolvePuzzle(c *Carcassonne) ([]*bg.BoardGameAction, error) {
+	if c.puzzle == nil {
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("game is not a puzzle"),
+			Status: bgerr.StatusInvalidAction,
+		}
+	}
+	searched := 0
+	solution, err := solvePuzzle(c.searchClone(), &searched)
+	if err != nil {
+		return nil, err
+	}
+	if solution == nil {
+		return nil, fmt.Errorf("puzzle has no solution")
+	}
+	return solution, nil
+}
+
+// solvePuzzle searches depth first returning nil when there is no solution
+func solvePuzzle(c *Carcassonne, searched *int) ([]*bg.BoardGameAction, error) {
+	if c.puzzle.solved {
+		return []*bg.BoardGameAction{}, nil
+	} else if c.puzzle.failed {
+		return nil, nil
+	}
+	moves, err := c.state.moves(c.state.turn)
+	if err != nil {
+		return nil, err
+	}
+	for _, move := range moves {
+		// tokens never help complete a city so only passing is tried
+		if c.puzzle.typ == PuzzleGoalCity && move.ActionType == ActionPlaceToken && !move.MoreDetails.(PlaceTokenActionDetails).Pass {
+			continue
+		}
+		*searched++
+		if *searched > maxPuzzleSearch {
+			return nil, fmt.Errorf("puzzle not solved after trying %d actions", maxPuzzleSearch)
+		}
+		next := c.searchClone()
+		if err := next.Do(move); err != nil {
+			return nil, err
+		}
+		solution, err := solvePuzzle(next, searched)
+		if err != nil {
+			return nil, err
+		}
+		if solution != nil {
+			return append([]*bg.BoardGameAction{move}, solution...), nil
+		}
+	}
+	return nil, nil
+}
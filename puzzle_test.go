@@ -0,0 +1,159 @@
+package go_carcassonne
+
+import (
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgn"
+	"github.com/stretchr/testify/assert"
+)
+
+const puzzleBGN = `[Game "Carcassonne"]
+[Teams "Solver"]
+[Seed "0"]
+[Name "Close the city"]
+[Position "0.0.19.0 - 0 7 0 15.0 - 21/21"]
+[Goal "city.0.0.t.1"]
+
+`
+
+func Test_Puzzle(t *testing.T) {
+	tests := []struct {
+		name     string
+		goal     string
+		solvable bool
+		actions  int
+		score    int
+	}{
+		{name: "complete city in one turn", goal: "city.0.0.t.1", solvable: true, actions: 1},
+		{name: "complete city in any number of turns", goal: "city.0.0.t.0", solvable: true, actions: 1},
+		{name: "score in one turn", goal: "points.4.1", solvable: true, actions: 2, score: 4},
+		{name: "score too many points", goal: "points.5.1", solvable: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+				Teams:       []string{TeamA},
+				MoreOptions: CarcassonneMoreOptions{Position: "0.0.19.0 - 0 7 0 15.0 - 21/21", Goal: test.goal},
+			})
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			solution, err := SolvePuzzle(carcassonne)
+			if !test.solvable {
+				assert.Error(t, err)
+				return
+			}
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			assert.Len(t, solution, test.actions)
+			for _, action := range solution {
+				if err := carcassonne.Do(action); err != nil {
+					t.Error(err)
+					t.FailNow()
+				}
+			}
+			snapshot, err := carcassonne.GetSnapshot(TeamA)
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			data := snapshot.MoreData.(CarcassonneSnapshotData)
+			assert.True(t, data.Puzzle.Solved)
+			assert.Equal(t, []string{TeamA}, snapshot.Winners)
+			assert.Equal(t, test.score, data.Scores[TeamA])
+			assert.Empty(t, snapshot.Targets)
+		})
+	}
+}
+
+func Test_Puzzle_Failed(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA},
+		MoreOptions: CarcassonneMoreOptions{Position: "0.0.19.0 - 0 7 0 15.0 - 21/21", Goal: "city.0.0.t.1"},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// place the city facing away from the start tile
+	err = carcassonne.Do(&bg.BoardGameAction{
+		Team:        TeamA,
+		ActionType:  ActionPlaceTile,
+		MoreDetails: PlaceTileActionDetails{X: 0, Y: 1, Tile: TileActionDetails{City, Farm, Farm, Farm, NilStructure, false, false}},
+	})
+	assert.Error(t, err)
+	err = carcassonne.Do(&bg.BoardGameAction{
+		Team:        TeamA,
+		ActionType:  ActionPlaceTile,
+		MoreDetails: PlaceTileActionDetails{X: 0, Y: -1, Tile: TileActionDetails{Farm, City, Farm, Farm, NilStructure, false, false}},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	err = carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceToken, MoreDetails: PlaceTokenActionDetails{Pass: true}})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	snapshot, err := carcassonne.GetSnapshot()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	data := snapshot.MoreData.(CarcassonneSnapshotData)
+	assert.True(t, data.Puzzle.Failed)
+	assert.Equal(t, 0, data.Puzzle.TurnsLeft)
+	assert.Empty(t, snapshot.Winners)
+	assert.Equal(t, "puzzle failed", snapshot.Message)
+	err = carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionRotateTileRight})
+	assert.Error(t, err)
+}
+
+func Test_Puzzle_BGN(t *testing.T) {
+	game, err := bgn.Parse(puzzleBGN)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	builder := Builder{}
+	loaded, err := builder.Load(game)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	carcassonne := loaded.(*Carcassonne)
+	solution, err := SolvePuzzle(carcassonne)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, action := range solution {
+		if err := carcassonne.Do(action); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	reloaded, err := builder.Load(carcassonne.GetBGN())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.True(t, reloaded.(*Carcassonne).puzzle.solved)
+
+	// puzzles are played by a single team
+	_, err = NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Goal: "points.4.1"},
+	})
+	assert.Error(t, err)
+	_, err = NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA},
+		MoreOptions: CarcassonneMoreOptions{Goal: "city.1.0.t.1"},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package go_carcassonne
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// rasterSamples is the number of samples taken along each axis of a pixel to smooth edges
+const rasterSamples = 3
+
+// rasterize draws d onto an image scaled by scale taking samples by samples points per pixel to smooth edges
+func (d *drawing) rasterize(scale float64, samples int) *image.RGBA {
+	width := int(math.Ceil(d.width * scale))
+	height := int(math.Ceil(d.height * scale))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if width == 0 || height == 0 {
+		return img
+	}
+	if samples <= 1 {
+		d.fill(img, scale)
+		return img
+	}
+	// draw at a higher resolution then average each block of samples into a single pixel
+	large := image.NewRGBA(image.Rect(0, 0, width*samples, height*samples))
+	d.fill(large, scale*float64(samples))
+	n := samples * samples
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var r, g, b, a int
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					c := large.RGBAAt(x*samples+sx, y*samples+sy)
+					r, g, b, a = r+int(c.R), g+int(c.G), b+int(c.B), a+int(c.A)
+				}
+			}
+			img.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+	return img
+}
+
+// fill draws every shape in d onto img scaled by s
+func (d *drawing) fill(img *image.RGBA, s float64) {
+	for _, sh := range d.shapes {
+		if len(sh.points) == 0 {
+			fillCircle(img, point{sh.center.X * s, sh.center.Y * s}, sh.radius*s, sh.fill)
+			continue
+		}
+		points := make([]point, len(sh.points))
+		for i, p := range sh.points {
+			points[i] = point{p.X * s, p.Y * s}
+		}
+		fillPolygon(img, points, sh.fill)
+	}
+}
+
+// fillPolygon fills every pixel whose center is inside the polygon using the even-odd rule
+func fillPolygon(img *image.RGBA, points []point, fill color.RGBA) {
+	bounds := img.Bounds()
+	minY, maxY := points[0].Y, points[0].Y
+	for _, p := range points {
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	crossings := make([]float64, 0, len(points))
+	for y := max(bounds.Min.Y, int(math.Floor(minY))); y < min(bounds.Max.Y, int(math.Ceil(maxY))); y++ {
+		cy := float64(y) + 0.5
+		crossings = crossings[:0]
+		for i, p1 := range points {
+			p2 := points[(i+1)%len(points)]
+			if (p1.Y <= cy) != (p2.Y <= cy) {
+				crossings = append(crossings, p1.X+(cy-p1.Y)*(p2.X-p1.X)/(p2.Y-p1.Y))
+			}
+		}
+		sort.Float64s(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			fillRow(img, y, crossings[i], crossings[i+1], fill)
+		}
+	}
+}
+
+// fillCircle fills every pixel whose center is inside the circle
+func fillCircle(img *image.RGBA, center point, radius float64, fill color.RGBA) {
+	bounds := img.Bounds()
+	for y := max(bounds.Min.Y, int(math.Floor(center.Y-radius))); y < min(bounds.Max.Y, int(math.Ceil(center.Y+radius))); y++ {
+		dy := float64(y) + 0.5 - center.Y
+		if dy*dy > radius*radius {
+			continue
+		}
+		dx := math.Sqrt(radius*radius - dy*dy)
+		fillRow(img, y, center.X-dx, center.X+dx, fill)
+	}
+}
+
+// fillRow fills the pixels in row y whose centers are between x1 and x2
+func fillRow(img *image.RGBA, y int, x1, x2 float64, fill color.RGBA) {
+	bounds := img.Bounds()
+	start := max(bounds.Min.X, int(math.Ceil(x1-0.5)))
+	end := min(bounds.Max.X, int(math.Ceil(x2-0.5)))
+	for x := start; x < end; x++ {
+		img.SetRGBA(x, y, fill)
+	}
+}
@@ -0,0 +1,134 @@
+package go_carcassonne
+
+import (
+	"math/rand"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+// playWithToken plays random actions until team has a token on the board
+func playWithToken(t *testing.T, carcassonne *Carcassonne, team string, random *rand.Rand) {
+	for i := 0; i < 200; i++ {
+		for _, token := range carcassonne.state.boardTokens {
+			if token.Team == team {
+				return
+			}
+		}
+		if err := carcassonne.Do(randomAction(carcassonne.state, random)); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	t.Error("no token placed")
+	t.FailNow()
+}
+
+func Test_Resign(t *testing.T) {
+	for _, rule := range []string{ResignReturn, ResignFreeze} {
+		t.Run(rule, func(t *testing.T) {
+			teams := []string{TeamA, TeamB, "TeamC"}
+			carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+				Teams:       teams,
+				MoreOptions: CarcassonneMoreOptions{Seed: 3, ResignTokens: rule},
+			})
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			random := rand.New(rand.NewSource(3))
+			playWithToken(t, carcassonne, TeamB, random)
+			onBoard := 7 - carcassonne.state.tokens[TeamB]
+			tiles := len(carcassonne.state.deck.tiles)
+
+			if err := carcassonne.Do(&bg.BoardGameAction{Team: TeamB, ActionType: ActionResign}); err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			assert.Error(t, carcassonne.Do(&bg.BoardGameAction{Team: TeamB, ActionType: ActionResign}))
+			assert.Len(t, carcassonne.state.deck.tiles, tiles+1)
+			if rule == ResignReturn {
+				assert.Equal(t, 7, carcassonne.state.tokens[TeamB])
+			} else {
+				assert.Equal(t, 7-onBoard, carcassonne.state.tokens[TeamB])
+			}
+
+			// the game continues without the resigned team
+			for len(carcassonne.state.winners) == 0 {
+				assert.NotEqual(t, TeamB, carcassonne.state.turn)
+				if err := carcassonne.Do(randomAction(carcassonne.state, random)); err != nil {
+					t.Error(err)
+					t.FailNow()
+				}
+			}
+			assert.NotContains(t, carcassonne.state.winners, TeamB)
+			snapshot, _ := carcassonne.GetSnapshot()
+			assert.Equal(t, []string{TeamB}, snapshot.MoreData.(CarcassonneSnapshotData).Resigned)
+
+			game := carcassonne.GetBGN()
+			builder := Builder{}
+			loaded, err := builder.Load(game)
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			assert.Equal(t, []string{TeamB}, loaded.(*Carcassonne).state.resigned)
+			assert.Equal(t, carcassonne.state.scores, loaded.(*Carcassonne).state.scores)
+			assert.Equal(t, carcassonne.state.winners, loaded.(*Carcassonne).state.winners)
+		})
+	}
+}
+
+func Test_Resign_LastTeam(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 123},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err := carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionResign}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, []string{TeamB}, carcassonne.state.winners)
+	assert.Equal(t, "q", string(carcassonne.GetBGN().Actions[0].ActionKey))
+}
+
+func Test_Resign_Partnership(t *testing.T) {
+	teams := []string{TeamA, TeamB, "TeamC", "TeamD"}
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       teams,
+		MoreOptions: CarcassonneMoreOptions{Seed: 123, Partnerships: [][]string{{TeamA, "TeamC"}, {TeamB, "TeamD"}}},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, team := range []string{TeamB, "TeamD"} {
+		if err := carcassonne.Do(&bg.BoardGameAction{Team: team, ActionType: ActionResign}); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	assert.Equal(t, []string{TeamA, "TeamC"}, carcassonne.state.winners)
+}
+
+func Test_Resign_Solo(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA},
+		MoreOptions: CarcassonneMoreOptions{Seed: 123, Solo: &SoloOptions{Target: 50}},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err := carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionResign}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.True(t, carcassonne.solo.lost)
+	assert.Empty(t, carcassonne.state.winners)
+}
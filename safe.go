@@ -0,0 +1,187 @@
+package go_carcassonne
+
+import (
+	"fmt"
+	"sync"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+	"github.com/quibbble/go-boardgame/pkg/bgn"
+)
+
+// subscriptionBuffer is the number of snapshots held for a subscriber before older snapshots are dropped
+const subscriptionBuffer = 8
+
+// SafeCarcassonne is a game of Carcassonne that can be used from many goroutines at once
+// snapshots are taken from a copy of the game so they can be read while the game continues
+type SafeCarcassonne struct {
+	mu            sync.Mutex
+	game          *Carcassonne
+	seats         *SeatController
+	subscriptions map[*Subscription]bool
+}
+
+// Subscription receives a snapshot of the game seen by its team each time an action is accepted
+type Subscription struct {
+	// C receives the current snapshot when subscribing then a new snapshot after every accepted action
+	// a subscriber that falls behind skips older snapshots but always receives the latest one
+	// C is closed after Unsubscribe
+	C <-chan *bg.BoardGameSnapshot
+
+	team  []string
+	c     chan *bg.BoardGameSnapshot
+	owner *SafeCarcassonne
+}
+
+func NewSafeCarcassonne(options *bg.BoardGameOptions) (*SafeCarcassonne, error) {
+	game, err := NewCarcassonne(options)
+	if err != nil {
+		return nil, err
+	}
+	return WrapCarcassonne(game), nil
+}
+
+// WrapCarcassonne makes game safe to use from many goroutines, game should not be used directly afterwards
+func WrapCarcassonne(game *Carcassonne) *SafeCarcassonne {
+	s := &SafeCarcassonne{
+		game:          game,
+		seats:         NewSeatController(game),
+		subscriptions: make(map[*Subscription]bool),
+	}
+	// subscribers see every action including each one played by a bot
+	s.seats.OnAction(func(*bg.BoardGameAction) { s.publish() })
+	return s
+}
+
+// Do performs action then lets bots play the turns of the seats they took over
+func (s *SafeCarcassonne) Do(action *bg.BoardGameAction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seats.Do(action)
+}
+
+// TakeOver has bot play for team until HandBack is called, see SeatController.TakeOver
+func (s *SafeCarcassonne) TakeOver(team string, bot Bot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seats.TakeOver(team, bot)
+}
+
+// HandBack returns control of team to its player
+func (s *SafeCarcassonne) HandBack(team string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seats.HandBack(team)
+}
+
+// BotTeams gets the teams currently played by bots in sorted order
+func (s *SafeCarcassonne) BotTeams() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seats.BotTeams()
+}
+
+// CheckClock handles the team whose turn it is running out of time, see Carcassonne.CheckClock
+// subscribers receive a snapshot if the timeout changed the game
+func (s *SafeCarcassonne) CheckClock() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	actions := len(s.game.actions)
+	if err := s.game.CheckClock(); err != nil {
+		return err
+	}
+	if len(s.game.actions) != actions {
+		s.publish()
+	}
+	// the timeout may have passed the turn to a bot
+	return s.seats.advance()
+}
+
+// publish pushes the latest snapshot to every subscriber, only called while the game is locked
+func (s *SafeCarcassonne) publish() {
+	for subscription := range s.subscriptions {
+		snapshot, err := s.game.Clone().GetSnapshot(subscription.team...)
+		if err != nil {
+			continue
+		}
+		subscription.push(snapshot)
+	}
+}
+
+func (s *SafeCarcassonne) GetSnapshot(team ...string) (*bg.BoardGameSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.Clone().GetSnapshot(team...)
+}
+
+func (s *SafeCarcassonne) GetBGN() *bgn.Game {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.GetBGN()
+}
+
+// Clone returns a copy of the game that is not safe to use from many goroutines
+func (s *SafeCarcassonne) Clone() *Carcassonne {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.game.Clone()
+}
+
+// Subscribe gets snapshots seen by team after each accepted action, or snapshots showing everything when no team is given
+func (s *SafeCarcassonne) Subscribe(team ...string) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(team) > 1 {
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("subscribe requires zero or one team"),
+			Status: bgerr.StatusTooManyTeams,
+		}
+	}
+	if len(team) == 1 && !contains(s.game.state.teams, team[0]) {
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("%s is not a team in the game", team[0]),
+			Status: bgerr.StatusUnknownTeam,
+		}
+	}
+	snapshot, err := s.game.Clone().GetSnapshot(team...)
+	if err != nil {
+		return nil, err
+	}
+	c := make(chan *bg.BoardGameSnapshot, subscriptionBuffer)
+	subscription := &Subscription{
+		C:     c,
+		team:  team,
+		c:     c,
+		owner: s,
+	}
+	subscription.push(snapshot)
+	s.subscriptions[subscription] = true
+	return subscription, nil
+}
+
+// Unsubscribe stops sending snapshots and closes C, calling it more than once has no effect
+func (s *Subscription) Unsubscribe() {
+	s.owner.mu.Lock()
+	defer s.owner.mu.Unlock()
+	if !s.owner.subscriptions[s] {
+		return
+	}
+	delete(s.owner.subscriptions, s)
+	close(s.c)
+}
+
+// push sends snapshot without blocking by dropping the oldest snapshot when the buffer is full
+// only called while the game is locked so there is never more than one sender
+func (s *Subscription) push(snapshot *bg.BoardGameSnapshot) {
+	for {
+		select {
+		case s.c <- snapshot:
+			return
+		default:
+		}
+		select {
+		case <-s.c:
+		default:
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package go_carcassonne
+
+import (
+	"encoding/json"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SafeCarcassonne(t *testing.T) {
+	var _ bg.BoardGameWithBGN = &SafeCarcassonne{}
+
+	safe, err := NewSafeCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 123},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	goroutines := runtime.NumGoroutine()
+
+	_, err = safe.Subscribe("TeamC")
+	assert.Equal(t, bgerr.StatusUnknownTeam, err.(*bgerr.Error).Status)
+
+	subscriptions := make([]*Subscription, 0)
+	for _, team := range [][]string{{TeamA}, {TeamB}, {}} {
+		subscription, err := safe.Subscribe(team...)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	// readers take snapshots and encode them while actions are performed
+	var wg sync.WaitGroup
+	last := make([]*bg.BoardGameSnapshot, len(subscriptions))
+	for i, subscription := range subscriptions {
+		wg.Add(1)
+		go func(i int, subscription *Subscription) {
+			defer wg.Done()
+			for snapshot := range subscription.C {
+				if _, err := json.Marshal(snapshot); err != nil {
+					t.Error(err)
+				}
+				last[i] = snapshot
+			}
+		}(i, subscription)
+	}
+	for r := 0; r < 2; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				snapshot, _ := safe.GetSnapshot()
+				_, _ = json.Marshal(snapshot)
+				_ = safe.GetBGN().String()
+			}
+		}()
+	}
+	random := rand.New(rand.NewSource(0))
+	for i := 0; i < 40; i++ {
+		if err := safe.Do(randomAction(safe.game.state, random)); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	want, err := safe.GetSnapshot()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, subscription := range subscriptions {
+		subscription.Unsubscribe()
+		subscription.Unsubscribe()
+	}
+	wg.Wait()
+
+	// every subscriber ends with the latest snapshot even if it skipped some
+	for i, snapshot := range last {
+		assert.Equal(t, len(want.Actions), len(snapshot.Actions), i)
+	}
+	assert.NotNil(t, last[0].MoreData.(CarcassonneSnapshotData).PlayTile)
+	assert.Nil(t, last[2].MoreData.(CarcassonneSnapshotData).PlayTile)
+
+	// unsubscribed channels stop receiving and nothing is left running
+	assert.NoError(t, safe.Do(randomAction(safe.game.state, random)))
+	assert.Len(t, safe.subscriptions, 0)
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines)
+}
@@ -0,0 +1,113 @@
+package go_carcassonne
+
+import (
+	"fmt"
+	"sort"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+)
+
+// SeatController lets bots take over the seats of teams whose players left and hands them back when they return
+// the bot continues with the team's tokens, score, and play tile and plays as soon as it is the team's turn
+type SeatController struct {
+	game *Carcassonne
+	bots map[string]Bot
+
+	// applied is called after each action the controller applies
+	applied func(action *bg.BoardGameAction)
+}
+
+func NewSeatController(game *Carcassonne) *SeatController {
+	return &SeatController{
+		game: game,
+		bots: make(map[string]Bot),
+	}
+}
+
+// OnAction sets f to be called after each action the controller applies whether made by a person or a bot
+func (s *SeatController) OnAction(f func(action *bg.BoardGameAction)) {
+	s.applied = f
+}
+
+// TakeOver has bot play for team from now on then plays any turns the bots now have to play
+func (s *SeatController) TakeOver(team string, bot Bot) error {
+	if !contains(s.game.teams(), team) {
+		return &bgerr.Error{
+			Err:    fmt.Errorf("%s is not a team in the game", team),
+			Status: bgerr.StatusUnknownTeam,
+		}
+	}
+	if s.bots[team] != nil {
+		return &bgerr.Error{
+			Err:    fmt.Errorf("%s is already played by a bot", team),
+			Status: bgerr.StatusInvalidAction,
+		}
+	}
+	s.bots[team] = bot
+	return s.advance()
+}
+
+// HandBack returns control of team to its player, a turn the bot has started is left for the player to finish
+func (s *SeatController) HandBack(team string) error {
+	if s.bots[team] == nil {
+		return &bgerr.Error{
+			Err:    fmt.Errorf("%s is not played by a bot", team),
+			Status: bgerr.StatusInvalidAction,
+		}
+	}
+	delete(s.bots, team)
+	return nil
+}
+
+// BotTeams gets the teams currently played by bots in sorted order
+func (s *SeatController) BotTeams() []string {
+	teams := make([]string, 0, len(s.bots))
+	for team := range s.bots {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+	return teams
+}
+
+// Do performs an action for a team played by a person then plays any turns of the bots that follow
+func (s *SeatController) Do(action *bg.BoardGameAction) error {
+	if s.bots[action.Team] != nil {
+		return &bgerr.Error{
+			Err:    fmt.Errorf("%s is played by a bot", action.Team),
+			Status: bgerr.StatusInvalidAction,
+		}
+	}
+	if err := s.do(action); err != nil {
+		return err
+	}
+	return s.advance()
+}
+
+// advance lets the bots play until the game ends or it is the turn of a team played by a person
+func (s *SeatController) advance() error {
+	for !s.game.over() {
+		bot := s.bots[s.game.state.turn]
+		if bot == nil {
+			return nil
+		}
+		action, err := bot.Action(s.game, s.game.state.turn)
+		if err != nil {
+			return err
+		}
+		if err := s.do(action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SeatController) do(action *bg.BoardGameAction) error {
+	if err := s.game.Do(action); err != nil {
+		return err
+	}
+	if s.applied != nil {
+		s.applied(action)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package go_carcassonne
+
+import (
+	"math/rand"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SeatController(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 123},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	seats := NewSeatController(carcassonne)
+	applied := 0
+	seats.OnAction(func(*bg.BoardGameAction) { applied++ })
+	assert.Equal(t, bgerr.StatusUnknownTeam, seats.TakeOver("TeamC", NewRandomBot(0)).(*bgerr.Error).Status)
+	assert.Error(t, seats.HandBack(TeamB))
+
+	// the bot plays TeamB's turns right after TeamA's
+	if err := seats.TakeOver(TeamB, NewGreedyBot(0)); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, []string{TeamB}, seats.BotTeams())
+	assert.Error(t, seats.TakeOver(TeamB, NewRandomBot(0)))
+	random := rand.New(rand.NewSource(123))
+	for i := 0; i < 5; i++ {
+		if err := seats.Do(randomAction(carcassonne.state, random)); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		assert.Equal(t, TeamA, carcassonne.state.turn)
+		assert.Equal(t, len(carcassonne.actions), applied)
+	}
+	assert.Error(t, seats.Do(&bg.BoardGameAction{Team: TeamB, ActionType: ActionRotateTileRight}))
+
+	// with every seat taken over the bots play to the end of the game
+	if err := seats.TakeOver(TeamA, NewRandomBot(0)); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.True(t, carcassonne.over())
+	assert.Equal(t, len(carcassonne.actions), applied)
+
+	assert.NoError(t, seats.HandBack(TeamA))
+	assert.Equal(t, []string{TeamB}, seats.BotTeams())
+}
+
+func Test_SafeCarcassonne_TakeOver(t *testing.T) {
+	safe, err := NewSafeCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 5},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	subscription, err := safe.Subscribe(TeamA)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer subscription.Unsubscribe()
+	<-subscription.C
+
+	if err := safe.TakeOver(TeamA, NewRandomBot(0)); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// a snapshot is sent for each action the bot played
+	actions := len(safe.GetBGN().Actions)
+	assert.Equal(t, actions, len(subscription.C))
+	var snapshot *bg.BoardGameSnapshot
+	for i := 0; i < actions; i++ {
+		snapshot = <-subscription.C
+	}
+	assert.Equal(t, TeamB, snapshot.Turn)
+	assert.Equal(t, []string{TeamA}, safe.BotTeams())
+	assert.NoError(t, safe.HandBack(TeamA))
+	assert.Empty(t, safe.BotTeams())
+}
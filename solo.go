@@ -0,0 +1,274 @@
+package go_carcassonne
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+
+	bg "github.com/quibbble/go-boardgame"
+)
+
+// AutomaTeam is the team played by the automa in a solo game
+const AutomaTeam = "Automa"
+
+// Automa decisions
+const (
+	// AutomaScore places the tile and token that give the automa the most points
+	AutomaScore = "score"
+
+	// AutomaBlock places the tile where it gives the solo team the fewest points then places the best token for the automa
+	AutomaBlock = "block"
+
+	// AutomaBuild places the tile that gives the automa the most points without placing a token
+	AutomaBuild = "build"
+)
+
+// defaultAutomaDecisions is the decision deck used when none is given
+var defaultAutomaDecisions = []string{
+	AutomaScore, AutomaScore, AutomaScore, AutomaScore,
+	AutomaBlock, AutomaBlock, AutomaBlock,
+	AutomaBuild, AutomaBuild, AutomaBuild,
+}
+
+// SoloOptions are the options for a game played by a single team
+// the team either tries to reach a target score before the tiles run out or plays against the automa
+type SoloOptions struct {
+	// Target is the score to reach to win when not playing against the automa
+	Target int
+
+	// Automa adds an opponent that plays from a decision deck
+	// this is a house rule variant and not the official solo rules of Carcassonne
+	Automa bool
+
+	// Decisions is the decision deck of the automa which is shuffled using the seed at the start and whenever it runs out
+	// the default deck is used when empty
+	Decisions []string
+}
+
+// SoloStatus is the progress of a solo game
+type SoloStatus struct {
+	Target   int    // 0 when playing against the automa
+	Decision string // last decision made by the automa
+	Lost     bool   // set when the target score is not reached
+}
+
+// solo plays the automa and checks the end of a solo game
+//
+// with a target score the game ends as soon as the target is reached or when the tiles run out
+// against the automa the automa never places farmers and wins ties
+type solo struct {
+	target    int
+	automa    bool
+	decisions []string
+	next      int   // index of the next decision
+	shuffles  int64 // number of times the decision deck was shuffled
+	seed      int64
+	decision  string
+	lost      bool
+}
+
+func newSolo(options *SoloOptions, seed int64) (*solo, error) {
+	if options.Automa && options.Target != 0 {
+		return nil, fmt.Errorf("cannot set a target score when playing against the automa")
+	} else if !options.Automa && options.Target <= 0 {
+		return nil, fmt.Errorf("got target %d but wanted a positive target score", options.Target)
+	}
+	decisions := options.Decisions
+	if len(decisions) == 0 {
+		decisions = defaultAutomaDecisions
+	}
+	for _, decision := range decisions {
+		if decision != AutomaScore && decision != AutomaBlock && decision != AutomaBuild {
+			return nil, fmt.Errorf("got decision %s but wanted %s, %s, or %s", decision, AutomaScore, AutomaBlock, AutomaBuild)
+		}
+	}
+	s := &solo{
+		target:    options.Target,
+		automa:    options.Automa,
+		decisions: append(make([]string, 0, len(decisions)), decisions...),
+		seed:      seed,
+	}
+	s.shuffle()
+	return s, nil
+}
+
+func (s *solo) clone() *solo {
+	c := *s
+	c.decisions = append(make([]string, 0, len(s.decisions)), s.decisions...)
+	return &c
+}
+
+// playAutoma plays the automa until it is the solo team's turn recording its placements as actions of the automa team
+func (c *Carcassonne) playAutoma() error {
+	for c.solo.automa && len(c.state.winners) == 0 && c.state.turn == AutomaTeam {
+		actions, err := c.solo.play(c.state)
+		if err != nil {
+			return err
+		}
+		for _, action := range actions {
+			if err := c.do(action); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// replay does the action at index of a recorded game
+// actions of the automa are skipped as the decision deck already played them but must match what was played
+func (c *Carcassonne) replay(index int, action *bg.BoardGameAction) error {
+	if c.solo == nil || !c.solo.automa || action.Team != AutomaTeam {
+		return c.Do(action)
+	}
+	if index >= len(c.actions) || c.actions[index].Team != AutomaTeam || c.actions[index].ActionType != action.ActionType ||
+		!slices.Equal(actionDetailsNotation(c.state.teams, c.actions[index]), actionDetailsNotation(c.state.teams, action)) {
+		return loadFailure(fmt.Errorf("action %d of the automa does not match its decision deck", index))
+	}
+	return nil
+}
+
+// update checks whether the game ended
+func (s *solo) update(st *state) error {
+	team := st.teams[0]
+	if s.automa {
+		// ties go to the automa
+		if len(st.winners) > 1 {
+			st.winners = []string{AutomaTeam}
+		}
+		return nil
+	}
+	if st.scores[team] >= s.target && !contains(st.resigned, team) {
+		st.winners = []string{team}
+	} else if len(st.winners) > 0 || contains(st.resigned, team) {
+		s.lost = true
+		st.winners = make([]string, 0)
+	}
+	return nil
+}
+
+// draw gets the next decision shuffling the decision deck when it runs out
+func (s *solo) draw() string {
+	if s.next >= len(s.decisions) {
+		s.shuffle()
+	}
+	decision := s.decisions[s.next]
+	s.next++
+	return decision
+}
+
+// shuffle shuffles the decision deck using a different source for each shuffle so the game can be replayed from its seed
+func (s *solo) shuffle() {
+	random := rand.New(rand.NewSource(s.seed + s.shuffles))
+	random.Shuffle(len(s.decisions), func(i, j int) {
+		s.decisions[i], s.decisions[j] = s.decisions[j], s.decisions[i]
+	})
+	s.shuffles++
+	s.next = 0
+}
+
+// play chooses the automa's tile and token placements using the next decision
+func (s *solo) play(st *state) ([]*bg.BoardGameAction, error) {
+	s.decision = s.draw()
+	team := st.teams[0]
+	var (
+		bestTile                *PlaceTileActionDetails
+		bestToken               *PlaceTokenActionDetails
+		bestAutoma, bestOpposed float64
+	)
+	for _, placement := range st.placements(AutomaTeam) {
+		placed := st.clone()
+		if err := placed.PlaceTile(AutomaTeam, placement.Slot, placement.Tile.tile(), placement.X, placement.Y); err != nil {
+			return nil, err
+		}
+		for _, token := range automaTokens(placed, s.decision == AutomaBuild) {
+			result := placed
+			if token != nil {
+				result = placed.clone()
+				if err := result.PlaceToken(AutomaTeam, token.Pass, token.X, token.Y, token.Type, token.Side); err != nil {
+					return nil, err
+				}
+			}
+			values, err := evaluate(result)
+			if err != nil {
+				return nil, err
+			}
+			automa, opposed := values[AutomaTeam], values[team]
+			better := automa > bestAutoma
+			if s.decision == AutomaBlock {
+				better = opposed < bestOpposed || (opposed == bestOpposed && automa > bestAutoma)
+			}
+			if bestTile == nil || better {
+				bestTile, bestToken = placement, token
+				bestAutoma, bestOpposed = automa, opposed
+			}
+		}
+	}
+	if bestTile == nil {
+		return nil, fmt.Errorf("automa cannot place tile")
+	}
+	actions := []*bg.BoardGameAction{{Team: AutomaTeam, ActionType: ActionPlaceTile, MoreDetails: *bestTile}}
+	if bestToken != nil {
+		actions = append(actions, &bg.BoardGameAction{Team: AutomaTeam, ActionType: ActionPlaceToken, MoreDetails: *bestToken})
+	}
+	return actions, nil
+}
+
+// automaTokens gets the token placements the automa may choose from after placing its tile
+// nil is returned alone when the token was already passed by the engine
+func automaTokens(placed *state, passOnly bool) []*PlaceTokenActionDetails {
+	if placed.turn != AutomaTeam || placed.playTiles[AutomaTeam] != nil || len(placed.winners) > 0 {
+		return []*PlaceTokenActionDetails{nil}
+	}
+	tokens := []*PlaceTokenActionDetails{{Pass: true}}
+	if passOnly {
+		return tokens
+	}
+	for _, target := range placed.targets() {
+		details, ok := target.MoreDetails.(PlaceTokenActionDetails)
+		// the automa never places farmers
+		if ok && !details.Pass && details.Type != Farmer {
+			tokens = append(tokens, &details)
+		}
+	}
+	return tokens
+}
+
+func (s *solo) status() *SoloStatus {
+	return &SoloStatus{
+		Target:   s.target,
+		Decision: s.decision,
+		Lost:     s.lost,
+	}
+}
+
+func (s *solo) message(team string) *Message {
+	return newMessage(MessageSoloLost, map[string]string{"Team": team, "Target": strconv.Itoa(s.target)})
+}
+
+// encodeSolo writes the Solo tag e.g. target.80 or automa.score.block.build
+func encodeSolo(options *SoloOptions) string {
+	if !options.Automa {
+		return fmt.Sprintf("target.%d", options.Target)
+	}
+	return strings.Join(append([]string{"automa"}, options.Decisions...), ".")
+}
+
+func decodeSolo(notation string) (*SoloOptions, error) {
+	fields := strings.Split(notation, ".")
+	switch fields[0] {
+	case "target":
+		if len(fields) != 2 {
+			return nil, loadFailure(fmt.Errorf("got %d but wanted 2 fields in solo target", len(fields)))
+		}
+		target, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, loadFailure(err)
+		}
+		return &SoloOptions{Target: target}, nil
+	case "automa":
+		return &SoloOptions{Automa: true, Decisions: fields[1:]}, nil
+	}
+	return nil, loadFailure(fmt.Errorf("got solo %s but wanted target or automa", fields[0]))
+}
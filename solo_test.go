@@ -0,0 +1,154 @@
+package go_carcassonne
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func playSolo(t *testing.T, options *SoloOptions, seed int64) *Carcassonne {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA},
+		MoreOptions: CarcassonneMoreOptions{Seed: seed, Solo: options},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	random := rand.New(rand.NewSource(seed))
+	for !carcassonne.over() {
+		if err := carcassonne.Do(randomAction(carcassonne.state, random)); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	return carcassonne
+}
+
+func Test_Solo_Target(t *testing.T) {
+	// the target cannot be reached so the game is lost once the tiles run out
+	carcassonne := playSolo(t, &SoloOptions{Target: 1000}, 1)
+	assert.True(t, carcassonne.solo.lost)
+	assert.Empty(t, carcassonne.state.winners)
+	assert.Equal(t, 0, carcassonne.state.deck.Size())
+	snapshot, err := carcassonne.GetSnapshot(TeamA)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, "TeamA did not reach the target score of 1000", snapshot.Message)
+	assert.True(t, snapshot.MoreData.(CarcassonneSnapshotData).Solo.Lost)
+	assert.Empty(t, snapshot.Targets)
+	err = carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionRotateTileRight})
+	assert.Error(t, err)
+
+	// the game ends as soon as the target is reached
+	carcassonne = playSolo(t, &SoloOptions{Target: 10}, 1)
+	assert.False(t, carcassonne.solo.lost)
+	assert.Equal(t, []string{TeamA}, carcassonne.state.winners)
+	assert.GreaterOrEqual(t, carcassonne.state.scores[TeamA], 10)
+	assert.Greater(t, carcassonne.state.deck.Size(), 0)
+}
+
+func Test_Solo_Automa(t *testing.T) {
+	for _, seed := range []int64{1, 2} {
+		carcassonne := playSolo(t, &SoloOptions{Automa: true}, seed)
+		assert.Equal(t, []string{TeamA, AutomaTeam}, carcassonne.state.teams)
+		assert.Len(t, carcassonne.state.winners, 1)
+		if carcassonne.state.scores[TeamA] <= carcassonne.state.scores[AutomaTeam] {
+			assert.Equal(t, []string{AutomaTeam}, carcassonne.state.winners)
+		}
+		// the automa's placements are recorded as its own actions
+		automa := 0
+		for _, action := range carcassonne.actions {
+			if action.Team == AutomaTeam {
+				assert.Contains(t, []string{ActionPlaceTile, ActionPlaceToken}, action.ActionType)
+				automa++
+			}
+		}
+		assert.Greater(t, automa, 0)
+		assert.Greater(t, carcassonne.state.featureScores[AutomaTeam][City]+carcassonne.state.featureScores[AutomaTeam][Road], 0)
+		assert.Equal(t, 0, carcassonne.state.featureScores[AutomaTeam][Farm])
+
+		builder := Builder{}
+		game := carcassonne.GetBGN()
+		assert.Equal(t, TeamA, game.Tags["Teams"])
+		loaded, err := builder.Load(game)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		assert.Equal(t, carcassonne.state.scores, loaded.(*Carcassonne).state.scores)
+		assert.Equal(t, carcassonne.state.winners, loaded.(*Carcassonne).state.winners)
+		assert.Equal(t, game.Actions, loaded.GetBGN().Actions)
+
+		// recorded actions of the automa must match its decision deck
+		for i, action := range game.Actions {
+			if action.TeamIndex == 1 {
+				game.Actions[i].ActionKey = rune(actionToNotation[ActionRotateTileRight][0])
+				break
+			}
+		}
+		_, err = builder.Load(game)
+		assert.Error(t, err)
+
+		// as must the details of where the automa placed
+		game = carcassonne.GetBGN()
+		for i, action := range game.Actions {
+			if action.TeamIndex == 1 && action.ActionKey == rune(actionToNotation[ActionPlaceTile][0]) {
+				game.Actions[i].Details = append([]string{"100"}, action.Details[1:]...)
+				break
+			}
+		}
+		_, err = builder.Load(game)
+		assert.Error(t, err)
+	}
+}
+
+func Test_Solo_Decisions(t *testing.T) {
+	s, err := newSolo(&SoloOptions{Automa: true, Decisions: []string{AutomaBlock, AutomaScore, AutomaBuild}}, 3)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// the deck is shuffled from the start and reshuffled once every decision is used
+	for i := 0; i < 2; i++ {
+		drawn := []string{s.draw(), s.draw(), s.draw()}
+		assert.ElementsMatch(t, []string{AutomaBlock, AutomaScore, AutomaBuild}, drawn)
+	}
+	assert.Equal(t, s.clone().draw(), s.draw())
+
+	// the same seed shuffles the same way while other seeds start with other orders
+	orders := make(map[string]bool)
+	for seed := int64(0); seed < 10; seed++ {
+		first, _ := newSolo(&SoloOptions{Automa: true}, seed)
+		second, _ := newSolo(&SoloOptions{Automa: true}, seed)
+		assert.Equal(t, first.decisions, second.decisions)
+		orders[strings.Join(first.decisions, ".")] = true
+	}
+	assert.Greater(t, len(orders), 1)
+}
+
+func Test_Solo_Invalid(t *testing.T) {
+	tests := []struct {
+		teams []string
+		solo  *SoloOptions
+	}{
+		{teams: []string{TeamA, TeamB}, solo: &SoloOptions{Target: 50}},
+		{teams: []string{TeamA}, solo: &SoloOptions{}},
+		{teams: []string{TeamA}, solo: &SoloOptions{Automa: true, Target: 50}},
+		{teams: []string{TeamA}, solo: &SoloOptions{Automa: true, Decisions: []string{"dance"}}},
+		{teams: []string{AutomaTeam}, solo: &SoloOptions{Automa: true}},
+		{teams: []string{TeamA}},
+	}
+	for _, test := range tests {
+		_, err := NewCarcassonne(&bg.BoardGameOptions{
+			Teams:       test.teams,
+			MoreOptions: CarcassonneMoreOptions{Solo: test.solo},
+		})
+		assert.Error(t, err)
+	}
+}
@@ -2,7 +2,6 @@ package go_carcassonne
 
 import (
 	"fmt"
-	"math/rand"
 	"sort"
 	"strings"
 
@@ -15,25 +14,41 @@ type state struct {
 	turn            string
 	teams           []string
 	winners         []string
-	playTiles       map[string]*tile // teams to the tiles to place onto the board at the start of any given turn
-	lastPlacedTiles map[string]*tile // the tiles that were last placed by each team
+	playTiles       map[string]*tile   // teams to the tiles to place onto the board at the start of any given turn
+	hands           map[string][]*tile // teams to the other tiles in their hand when holding more than one tile
+	lastPlacedTiles map[string]*tile   // the tiles that were last placed by each team
 	board           *board
-	boardTokens     []*token       // a list of tokens currently on the board
-	tokens          map[string]int // number of tokens each team can play
-	scores          map[string]int // points of each team
+	boardTokens     []*token                  // a list of tokens currently on the board
+	tokens          map[string]int            // number of tokens each team can play
+	scores          map[string]int            // points of each team
+	featureScores   map[string]map[string]int // points of each team by structure type
 	deck            *deck
+	partnerships    [][]string // groups of teams sharing a combined score, nil if teams play alone
+	sharedMajority  bool       // whether tokens of partners count together when finding who scores a structure
+	resigned        []string   // teams that left the game in the order they resigned
+	resignTokens    string     // what happens to the tokens of resigning teams, see ResignReturn and ResignFreeze
+	handSize        int        // number of tiles each team holds
+	publicHands     bool       // whether every team can see the tiles held by all teams
+	reveal          int        // number of tiles at the top of the deck everyone can see
 }
 
-func newState(teams []string, random *rand.Rand) *state {
+// teamTokens is the number of tokens each team starts with
+const teamTokens = 7
+
+func newState(teams []string, seed int64, order []int) *state {
 	tokens := make(map[string]int)
 	scores := make(map[string]int)
+	featureScores := make(map[string]map[string]int)
 	playTiles := make(map[string]*tile)
+	hands := make(map[string][]*tile)
 	lastPlacedTiles := make(map[string]*tile)
 	for _, team := range teams {
-		tokens[team] = 7
+		hands[team] = make([]*tile, 0)
+		tokens[team] = teamTokens
 		scores[team] = 0
+		featureScores[team] = map[string]int{City: 0, Road: 0, Cloister: 0, Farm: 0}
 	}
-	deck := newDeck(random)
+	deck := newDeck(seed, order)
 	for _, team := range teams {
 		tile, _ := deck.Draw()
 		playTiles[team] = tile
@@ -44,19 +59,87 @@ func newState(teams []string, random *rand.Rand) *state {
 		teams:           teams,
 		winners:         make([]string, 0),
 		playTiles:       playTiles,
+		hands:           hands,
 		lastPlacedTiles: lastPlacedTiles,
 		board:           newBoard(),
 		boardTokens:     make([]*token, 0),
 		tokens:          tokens,
 		scores:          scores,
+		featureScores:   featureScores,
 		deck:            deck,
+		resigned:        make([]string, 0),
+		resignTokens:    ResignReturn,
+		handSize:        1,
+	}
+}
+
+// clone creates a deep copy of the state such that changes to the copy never affect the original
+// tokens are shared as they are never modified once created
+func (s *state) clone() *state {
+	board, mapping := s.board.clone()
+	playTiles := make(map[string]*tile, len(s.playTiles))
+	for team, tile := range s.playTiles {
+		if tile != nil {
+			tile = tile.copy()
+		}
+		playTiles[team] = tile
+	}
+	hands := make(map[string][]*tile, len(s.hands))
+	for team, hand := range s.hands {
+		hands[team] = make([]*tile, 0, len(hand))
+		for _, tile := range hand {
+			hands[team] = append(hands[team], tile.copy())
+		}
+	}
+	lastPlacedTiles := make(map[string]*tile, len(s.lastPlacedTiles))
+	for team, tile := range s.lastPlacedTiles {
+		if tile != nil {
+			tile = mapping[tile]
+		}
+		lastPlacedTiles[team] = tile
+	}
+	tokens := make(map[string]int, len(s.tokens))
+	for team, amount := range s.tokens {
+		tokens[team] = amount
+	}
+	scores := make(map[string]int, len(s.scores))
+	for team, score := range s.scores {
+		scores[team] = score
+	}
+	featureScores := make(map[string]map[string]int, len(s.featureScores))
+	for team, features := range s.featureScores {
+		featureScores[team] = make(map[string]int, len(features))
+		for feature, score := range features {
+			featureScores[team][feature] = score
+		}
+	}
+	return &state{
+		turn:            s.turn,
+		teams:           append(make([]string, 0, len(s.teams)), s.teams...),
+		winners:         append(make([]string, 0, len(s.winners)), s.winners...),
+		playTiles:       playTiles,
+		hands:           hands,
+		lastPlacedTiles: lastPlacedTiles,
+		board:           board,
+		boardTokens:     append(make([]*token, 0, len(s.boardTokens)), s.boardTokens...),
+		tokens:          tokens,
+		scores:          scores,
+		featureScores:   featureScores,
+		deck:            s.deck.clone(),
+		partnerships:    s.partnerships,
+		sharedMajority:  s.sharedMajority,
+		resigned:        append(make([]string, 0, len(s.resigned)), s.resigned...),
+		resignTokens:    s.resignTokens,
+		handSize:        s.handSize,
+		publicHands:     s.publicHands,
+		reveal:          s.reveal,
 	}
 }
 
 func (s *state) RotateTileRight(team string) error {
 	if s.playTiles[team] == nil {
 		return &bgerr.Error{
-			Err:    fmt.Errorf("cannot rotate tile"),
+			Err:    &WrongPhaseError{Team: team, Action: ActionRotateTileRight, Phase: s.phase(team)},
 			Status: bgerr.StatusInvalidAction,
 		}
 	}
@@ -67,7 +150,7 @@ func (s *state) RotateTileRight(team string) error {
 func (s *state) RotateTileLeft(team string) error {
 	if s.playTiles[team] == nil {
 		return &bgerr.Error{
-			Err:    fmt.Errorf("cannot rotate tile"),
+			Err:    &WrongPhaseError{Team: team, Action: ActionRotateTileLeft, Phase: s.phase(team)},
 			Status: bgerr.StatusInvalidAction,
 		}
 	}
@@ -75,16 +158,38 @@ func (s *state) RotateTileLeft(team string) error {
 	return nil
 }
 
-func (s *state) PlaceTile(team string, tile *tile, x, y int) error {
+// PlaceTile places the tile in slot of team's hand where slot 0 is the play tile
+func (s *state) PlaceTile(team string, slot int, tile *tile, x, y int) error {
 	if team != s.turn {
 		return &bgerr.Error{
 			Err:    fmt.Errorf("%s cannot play on %s turn", team, s.turn),
 			Status: bgerr.StatusWrongTurn,
 		}
 	}
-	if s.playTiles[team] == nil || !tile.equals(s.playTiles[team]) {
+	if slot < 0 || slot > len(s.hands[team]) {
 		return &bgerr.Error{
-			Err:    fmt.Errorf("%s cannot place tile %+v", team, tile),
+			Err:    fmt.Errorf("%s has no tile in slot %d", team, slot),
+			Status: bgerr.StatusInvalidActionDetails,
+		}
+	}
+	if slot > 0 && s.playTiles[team] != nil {
+		// the chosen tile becomes the play tile and the play tile takes its slot
+		s.playTiles[team], s.hands[team][slot-1] = s.hands[team][slot-1], s.playTiles[team]
+		if err := s.PlaceTile(team, 0, tile, x, y); err != nil {
+			s.playTiles[team], s.hands[team][slot-1] = s.hands[team][slot-1], s.playTiles[team]
+			return err
+		}
+		return nil
+	}
+	if s.playTiles[team] == nil {
+		return &bgerr.Error{
+			Err:    &WrongPhaseError{Team: team, Action: ActionPlaceTile, Phase: s.phase(team)},
+			Status: bgerr.StatusInvalidAction,
+		}
+	}
+	if !tile.equals(s.playTiles[team]) {
+		return &bgerr.Error{
+			Err:    &WrongTileError{Team: team, Tile: tileToActionDetails(tile)},
 			Status: bgerr.StatusInvalidActionDetails,
 		}
 	}
@@ -121,7 +226,7 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 	}
 	if s.playTiles[team] != nil {
 		return &bgerr.Error{
-			Err:    fmt.Errorf("cannot place token"),
+			Err:    &WrongPhaseError{Team: team, Action: ActionPlaceToken, Phase: s.phase(team)},
 			Status: bgerr.StatusInvalidAction,
 		}
 	}
@@ -129,7 +234,7 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 	if !pass {
 		if s.lastPlacedTiles[team].X != x || s.lastPlacedTiles[team].Y != y {
 			return &bgerr.Error{
-				Err:    fmt.Errorf("cannot place token on tile at %d,%d", s.lastPlacedTiles[team].X, s.lastPlacedTiles[team].Y),
+				Err:    &WrongLocationError{X: x, Y: y, TileX: s.lastPlacedTiles[team].X, TileY: s.lastPlacedTiles[team].Y},
 				Status: bgerr.StatusInvalidAction,
 			}
 		}
@@ -157,7 +262,7 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 		}
 		if s.tokens[team] <= 0 {
 			return &bgerr.Error{
-				Err:    fmt.Errorf("not enough tokens to place for team %s", team),
+				Err:    &NoTokensError{Team: team},
 				Status: bgerr.StatusInvalidAction,
 			}
 		}
@@ -174,7 +279,7 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 			tokens := tokensInStructure(s.boardTokens, road)
 			if len(tokens) > 0 {
 				return &bgerr.Error{
-					Err:    fmt.Errorf("cannot place token on road that is already claimed"),
+					Err:    &ClaimedError{X: x, Y: y, Side: side, Structure: Road, Owners: owners(tokens)},
 					Status: bgerr.StatusInvalidAction,
 				}
 			}
@@ -189,7 +294,7 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 			tokens := tokensInStructure(s.boardTokens, city)
 			if len(tokens) > 0 {
 				return &bgerr.Error{
-					Err:    fmt.Errorf("cannot place token on city that is already claimed"),
+					Err:    &ClaimedError{X: x, Y: y, Side: side, Structure: City, Owners: owners(tokens)},
 					Status: bgerr.StatusInvalidAction,
 				}
 			}
@@ -204,7 +309,7 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 			tokens := tokensInStructure(s.boardTokens, farm)
 			if len(tokens) > 0 {
 				return &bgerr.Error{
-					Err:    fmt.Errorf("cannot place token on farm that is already claimed"),
+					Err:    &ClaimedError{X: x, Y: y, Side: side, Structure: Farm, Owners: owners(tokens)},
 					Status: bgerr.StatusInvalidAction,
 				}
 			}
@@ -248,9 +353,9 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 							Status: bgerr.StatusInvalidAction,
 						}
 					}
-					winners := pointsWinners(inside)
+					winners := s.pointsWinners(inside)
 					for _, winner := range winners {
-						s.scores[winner] += points
+						s.addPoints(winner, City, points)
 					}
 					// remove inside from board and add back to tokens pile
 					for _, token := range inside {
@@ -297,9 +402,9 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 							Status: bgerr.StatusInvalidAction,
 						}
 					}
-					winners := pointsWinners(inside)
+					winners := s.pointsWinners(inside)
 					for _, winner := range winners {
-						s.scores[winner] += points
+						s.addPoints(winner, Road, points)
 					}
 					// remove inside from board and add back to tokens pile
 					for _, token := range inside {
@@ -343,7 +448,7 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 				for _, token := range s.boardTokens {
 					if token.Type == Monk && token.X == location[0] && token.Y == location[1] {
 						// add to score
-						s.scores[token.Team] += count + 1
+						s.addPoints(token.Team, Cloister, count+1)
 						// remove inside from board and add back to tokens pile
 						s.tokens[token.Team]++
 						s.boardTokens = removeTokens(s.boardTokens, token)
@@ -357,213 +462,321 @@ func (s *state) PlaceToken(team string, pass bool, x, y int, typ, side string) e
 	}
 
 	// draw tile for player
-	if !s.deck.Empty() {
-		tile, _ := s.deck.Draw()
-		s.playTiles[s.turn] = tile
-	}
+	s.refill(s.turn)
 
 	tilesInHands := 0
-	for _, tile := range s.playTiles {
+	for team, tile := range s.playTiles {
 		if tile != nil {
 			tilesInHands++
 		}
+		tilesInHands += len(s.hands[team])
 	}
 
 	if tilesInHands > 0 {
-		// next turn
-		for idx, team := range s.teams {
-			if team == s.turn {
-				s.turn = s.teams[(idx+1)%len(s.teams)]
-				break
-			}
-		}
-
-		// edge case where play tile isn't playable so re-draw
-		if !s.board.playable(s.playTiles[s.turn]) {
-			if !s.deck.Empty() {
-				tried := []*tile{s.playTiles[s.turn]}
-				retryLimit := s.deck.Size()
-				for i := 0; i < retryLimit; i++ {
-					tile, _ := s.deck.Draw()
-					if s.board.playable(tile) {
-						s.playTiles[s.turn] = tile
-						s.deck.Add(tried...)
-						tried = nil
-						break
-					} else {
-						tried = append(tried, tile)
-					}
-				}
-				if tried != nil {
-					// edge case where no tile in the deck is playable so end the game instead
-					if err := s.score(); err != nil {
-						return err
-					}
+		return s.nextTurn()
+	} else {
+		// all tiles have been played so score
+		if err := s.score(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// phase gets the action team has to make next, empty if it has nothing to do
+func (s *state) phase(team string) string {
+	if len(s.winners) > 0 {
+		return ""
+	}
+	if s.playTiles[team] != nil {
+		return ActionPlaceTile
+	}
+	if team == s.turn {
+		return ActionPlaceToken
+	}
+	return ""
+}
+
+// nextTurn passes the turn to the next team still playing that has a tile and redraws its tile if it cannot be placed
+func (s *state) nextTurn() error {
+	idx := indexOf(s.teams, s.turn)
+	for i := 1; i <= len(s.teams); i++ {
+		team := s.teams[(idx+i)%len(s.teams)]
+		if contains(s.resigned, team) {
+			continue
+		}
+		// edge case where a tile was returned to the deck by a resigning team after the team could not draw
+		if s.playTiles[team] == nil {
+			s.refill(team)
+		}
+		if s.playTiles[team] != nil {
+			s.turn = team
+			break
+		}
+	}
+	if s.playTiles[s.turn] == nil {
+		// no team still playing has a tile so end the game
+		return s.score()
+	}
+
+	// edge case where play tile isn't playable so re-draw unless another tile in hand can be played
+	if !s.handPlayable(s.turn) {
+		if !s.deck.Empty() {
+			tried := []*tile{s.playTiles[s.turn]}
+			retryLimit := s.deck.Size()
+			for i := 0; i < retryLimit; i++ {
+				tile, _ := s.deck.Draw()
+				if s.board.playable(tile) {
+					s.playTiles[s.turn] = tile
+					// revealed tiles stay on top of the deck in the order everyone saw
+					s.deck.Add(s.reveal, tried...)
+					tried = nil
+					break
+				} else {
+					tried = append(tried, tile)
 				}
-			} else {
-				// edge case where tiles still remain but cannot be played so end the game instead
+			}
+			if tried != nil {
+				// edge case where no tile in the deck is playable so end the game instead
 				if err := s.score(); err != nil {
 					return err
 				}
 			}
-		}
-	} else {
-		// all tiles have been played so score
-		if err := s.score(); err != nil {
-			return err
+		} else {
+			// edge case where tiles still remain but cannot be played so end the game instead
+			if err := s.score(); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// deal tops up the hand of every team to size tiles
+func (s *state) deal(size int) {
+	s.handSize = size
+	for _, team := range s.teams {
+		if s.playTiles[team] == nil && team == s.turn {
+			// the team is placing a token so its play tile is not drawn until its turn ends
+			for len(s.hands[team]) < size-1 && !s.deck.Empty() {
+				tile, _ := s.deck.Draw()
+				s.hands[team] = append(s.hands[team], tile)
+			}
+			continue
+		}
+		s.refill(team)
+	}
+}
+
+// refill draws a tile into team's hand after placing its play tile then takes the first tile in hand as the new play tile
+func (s *state) refill(team string) {
+	hand := append(make([]*tile, 0, s.handSize), s.hands[team]...)
+	if s.playTiles[team] != nil {
+		hand = append([]*tile{s.playTiles[team]}, hand...)
+	}
+	for len(hand) < s.handSize && !s.deck.Empty() {
+		tile, _ := s.deck.Draw()
+		hand = append(hand, tile)
+	}
+	s.playTiles[team] = nil
+	s.hands[team] = make([]*tile, 0)
+	if len(hand) > 0 {
+		s.playTiles[team] = hand[0]
+		s.hands[team] = hand[1:]
+	}
+}
+
+// hand gets all tiles team holds where the index is the slot of the tile
+func (s *state) hand(team string) []*tile {
+	if s.playTiles[team] == nil {
+		return s.hands[team]
+	}
+	return append([]*tile{s.playTiles[team]}, s.hands[team]...)
+}
+
+// revealed gets the tiles at the top of the deck everyone can see in the order they will be drawn
+func (s *state) revealed() []*tile {
+	revealed := make([]*tile, 0, s.reveal)
+	for i := len(s.deck.tiles) - 1; i >= 0 && len(revealed) < s.reveal; i-- {
+		revealed = append(revealed, s.deck.tiles[i])
+	}
+	return revealed
+}
+
+// handPlayable checks whether any tile in team's hand can be placed
+func (s *state) handPlayable(team string) bool {
+	for _, t := range s.hand(team) {
+		if s.board.playable(t) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *state) score() error {
 	// score incomplete roads, cities, and cloister and score farms
-	for len(s.boardTokens) > 0 {
-		token := s.boardTokens[0]
-		switch token.Type {
+	results, err := s.endGameScoring()
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		// add points
+		for _, winner := range result.winners {
+			s.addPoints(winner, result.typ, result.points)
+		}
+		// remove inside from board and add back to tokens pile
+		for _, token := range result.tokens {
+			s.tokens[token.Team]++
+		}
+		s.boardTokens = removeTokens(s.boardTokens, result.tokens...)
+		switch result.typ {
+		case City, Road:
+			// set color of incomplete
+			for _, n := range result.structure.nodes {
+				for _, side := range n.sides {
+					n.tile.Teams[side] = result.winners
+				}
+			}
+		case Cloister:
+			// set color of incomplete
+			result.tile.CenterTeam = result.winners[0]
+		case Farm:
+			// set color of farmland
+			for _, n := range result.structure.nodes {
+				// get number of city sides
+				numCities := 0
+				for _, section := range n.tile.Sides {
+					if section == City {
+						numCities++
+					}
+				}
+				// edge case where two adjacent disconnected city sections leads to uncolored farmland between them
+				if !n.tile.ConnectedCitySides && numCities == 2 {
+					for _, farmSide := range FarmSides {
+						n.tile.FarmTeams[farmSide] = result.winners
+					}
+				} else {
+					// otherwise, do normal coloring
+					for _, farmSide := range n.sides {
+						n.tile.FarmTeams[farmSide] = result.winners
+					}
+				}
+			}
+		}
+	}
+	// winner is team with the highest score
+	s.winners = s.highestScorers(s.resigned...)
+	return nil
+}
+
+// scoring is the points awarded for a single structure
+type scoring struct {
+	typ       string     // type of structure scored
+	points    int        // points awarded to each winner
+	winners   []string   // teams awarded the points
+	tokens    []*token   // tokens inside the structure
+	structure *structure // the city, road, or farm scored
+	tile      *tile      // the cloister tile scored
+}
+
+// endGameScoring gets the scoring of all structures containing tokens as if the game ended now without modifying the state
+func (s *state) endGameScoring() ([]*scoring, error) {
+	results := make([]*scoring, 0)
+	remaining := s.boardTokens
+	for len(remaining) > 0 {
+		first := remaining[0]
+		result := &scoring{
+			typ:     NilStructure,
+			winners: make([]string, 0),
+			tokens:  []*token{first},
+		}
+		switch first.Type {
 		case Knight:
-			city, err := s.board.generateCity(token.X, token.Y, token.Side)
+			city, err := s.board.generateCity(first.X, first.Y, first.Side)
 			if err != nil {
-				return &bgerr.Error{
+				return nil, &bgerr.Error{
 					Err:    err,
 					Status: bgerr.StatusInvalidAction,
 				}
 			}
-			// score and add points
 			points, err := scoreCity(city)
 			if err != nil {
-				return &bgerr.Error{
+				return nil, &bgerr.Error{
 					Err:    err,
 					Status: bgerr.StatusInvalidAction,
 				}
 			}
-			inside := tokensInStructure(s.boardTokens, city)
-			winners := pointsWinners(inside)
-			for _, winner := range winners {
-				s.scores[winner] += points
-			}
-			// remove inside from board and add back to tokens pile
-			for _, token := range inside {
-				s.tokens[token.Team]++
-			}
-			s.boardTokens = removeTokens(s.boardTokens, inside...)
-			// set color of incomplete
-			for _, n := range city.nodes {
-				for _, side := range n.sides {
-					n.tile.Teams[side] = winners
-				}
-			}
+			result.typ = City
+			result.points = points
+			result.structure = city
+			result.tokens = tokensInStructure(remaining, city)
+			result.winners = s.pointsWinners(result.tokens)
 		case Thief:
-			road, err := s.board.generateRoad(token.X, token.Y, token.Side)
+			road, err := s.board.generateRoad(first.X, first.Y, first.Side)
 			if err != nil {
-				return &bgerr.Error{
+				return nil, &bgerr.Error{
 					Err:    err,
 					Status: bgerr.StatusInvalidAction,
 				}
 			}
-			// score and add points
 			points, err := scoreRoad(road)
 			if err != nil {
-				return &bgerr.Error{
+				return nil, &bgerr.Error{
 					Err:    err,
 					Status: bgerr.StatusInvalidAction,
 				}
 			}
-			inside := tokensInStructure(s.boardTokens, road)
-			winners := pointsWinners(inside)
-			for _, winner := range winners {
-				s.scores[winner] += points
-			}
-			// remove inside from board and add back to tokens pile
-			for _, token := range inside {
-				s.tokens[token.Team]++
-			}
-			s.boardTokens = removeTokens(s.boardTokens, inside...)
-			// set color of incomplete
-			for _, n := range road.nodes {
-				for _, side := range n.sides {
-					n.tile.Teams[side] = winners
-				}
-			}
+			result.typ = Road
+			result.points = points
+			result.structure = road
+			result.tokens = tokensInStructure(remaining, road)
+			result.winners = s.pointsWinners(result.tokens)
 		case Monk:
-			tile := s.board.tile(token.X, token.Y)
+			tile := s.board.tile(first.X, first.Y)
 			if tile != nil && tile.Center == Cloister {
-				count, err := s.board.tilesSurroundingCloister(token.X, token.Y)
+				count, err := s.board.tilesSurroundingCloister(first.X, first.Y)
 				if err != nil {
-					return &bgerr.Error{
+					return nil, &bgerr.Error{
 						Err:    err,
 						Status: bgerr.StatusInvalidAction,
 					}
 				}
-				s.scores[token.Team] += count + 1
-				// remove inside from board and add back to tokens pile
-				s.tokens[token.Team]++
-				s.boardTokens = removeTokens(s.boardTokens, token)
-				// set color of incomplete
-				tile.CenterTeam = token.Team
+				result.typ = Cloister
+				result.points = count + 1
+				result.tile = tile
+				result.winners = []string{first.Team}
 			}
 		case Farmer:
-			farm, err := s.board.generateFarm(token.X, token.Y, token.Side)
+			farm, err := s.board.generateFarm(first.X, first.Y, first.Side)
 			if err != nil {
-				return &bgerr.Error{
+				return nil, &bgerr.Error{
 					Err:    err,
 					Status: bgerr.StatusInvalidAction,
 				}
 			}
-			// score and add points
 			points, err := scoreFarm(farm, s.board.completeCities)
 			if err != nil {
-				return &bgerr.Error{
+				return nil, &bgerr.Error{
 					Err:    err,
 					Status: bgerr.StatusInvalidAction,
 				}
 			}
-			inside := tokensInStructure(s.boardTokens, farm)
-			winners := pointsWinners(inside)
-			for _, winner := range winners {
-				s.scores[winner] += points
-			}
-			// remove inside from board and add back to tokens pile
-			for _, token := range inside {
-				s.tokens[token.Team]++
-			}
-			s.boardTokens = removeTokens(s.boardTokens, inside...)
-			// set color of farmland
-			for _, n := range farm.nodes {
-				// get number of city sides
-				numCities := 0
-				for _, section := range n.tile.Sides {
-					if section == City {
-						numCities++
-					}
-				}
-				// edge case where two adjacent disconnected city sections leads to uncolored farmland between them
-				if !n.tile.ConnectedCitySides && numCities == 2 {
-					for _, farmSide := range FarmSides {
-						n.tile.FarmTeams[farmSide] = winners
-					}
-				} else {
-					// otherwise, do normal coloring
-					for _, farmSide := range n.sides {
-						n.tile.FarmTeams[farmSide] = winners
-					}
-				}
-			}
-		}
-	}
-	// winner is team with the highest score
-	max := 0
-	winners := make([]string, 0)
-	for p, score := range s.scores {
-		if score > max {
-			max = score
-			winners = []string{p}
-		} else if score == max {
-			winners = append(winners, p)
+			result.typ = Farm
+			result.points = points
+			result.structure = farm
+			result.tokens = tokensInStructure(remaining, farm)
+			result.winners = s.pointsWinners(result.tokens)
 		}
+		remaining = removeTokens(remaining, result.tokens...)
+		results = append(results, result)
 	}
-	s.winners = winners
-	return nil
+	return results, nil
+}
+
+// addPoints adds points to team's score keeping track of the structure type that awarded the points
+func (s *state) addPoints(team, typ string, points int) {
+	s.scores[team] += points
+	s.featureScores[team][typ] += points
 }
 
 func (s *state) SetWinners(winners []string) error {
@@ -579,6 +792,89 @@ func (s *state) SetWinners(winners []string) error {
 	return nil
 }
 
+// Resign removes team from the turn rotation and from the winners, the game continues while at least two teams are playing
+// the tile team was holding is put back on top of the deck and its tokens are returned or frozen on the board
+func (s *state) Resign(team string) error {
+	if len(s.winners) > 0 {
+		return &bgerr.Error{
+			Err:    fmt.Errorf("%s game already completed", key),
+			Status: bgerr.StatusGameOver,
+		}
+	}
+	if !contains(s.teams, team) {
+		return &bgerr.Error{
+			Err:    fmt.Errorf("%s is not a team in the game", team),
+			Status: bgerr.StatusUnknownTeam,
+		}
+	}
+	if contains(s.resigned, team) {
+		return &bgerr.Error{
+			Err:    fmt.Errorf("%s already resigned", team),
+			Status: bgerr.StatusInvalidAction,
+		}
+	}
+	if s.turn == team && s.playTiles[team] == nil {
+		// finish the turn so structures completed by the placed tile are scored
+		if err := s.PlaceToken(team, true, 0, 0, "", ""); err != nil {
+			return err
+		}
+		if len(s.winners) > 0 {
+			return nil
+		}
+	}
+	s.resigned = append(s.resigned, team)
+	hand := s.hand(team)
+	for i := len(hand) - 1; i >= 0; i-- {
+		s.deck.PutBack(hand[i])
+	}
+	s.playTiles[team] = nil
+	s.hands[team] = make([]*tile, 0)
+	if s.resignTokens == ResignReturn {
+		for _, token := range s.boardTokens {
+			if token.Team == team {
+				s.boardTokens = removeTokens(s.boardTokens, token)
+				s.tokens[team]++
+			}
+		}
+	}
+	playing := s.playing()
+	if len(s.teams) > 1 && s.oneSide(playing) {
+		// the teams still playing win as everyone else resigned
+		s.winners = playing
+		return nil
+	}
+	if s.turn == team {
+		return s.nextTurn()
+	}
+	return nil
+}
+
+// playing gets the teams that have not resigned
+func (s *state) playing() []string {
+	playing := make([]string, 0, len(s.teams))
+	for _, team := range s.teams {
+		if !contains(s.resigned, team) {
+			playing = append(playing, team)
+		}
+	}
+	return playing
+}
+
+// oneSide checks whether teams are a single team or all in the same partnership
+func (s *state) oneSide(teams []string) bool {
+	if len(teams) <= 1 {
+		return true
+	} else if len(s.partnerships) == 0 {
+		return false
+	}
+	for _, team := range teams {
+		if s.partnership(team) != s.partnership(teams[0]) {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *state) targets() []*bg.BoardGameAction {
 	targets := make([]*bg.BoardGameAction, 0)
 	if s.playTiles[s.turn] != nil {
@@ -593,13 +889,7 @@ func (s *state) targets() []*bg.BoardGameAction {
 		// find all valid places to play tile
 		emptySpaces := s.board.getEmptySpaces()
 		for _, emptySpace := range emptySpaces {
-			valid := true
-			for _, side := range Sides {
-				if emptySpace.adjacent[side] != nil && emptySpace.adjacent[side].Sides[AcrossSide[side]] != s.playTiles[s.turn].Sides[side] {
-					valid = false
-				}
-			}
-			if valid {
+			if fits(emptySpace, s.playTiles[s.turn]) {
 				targets = append(targets, &bg.BoardGameAction{
 					Team:       s.turn,
 					ActionType: ActionPlaceTile,
@@ -610,6 +900,18 @@ func (s *state) targets() []*bg.BoardGameAction {
 				})
 			}
 		}
+		// the other tiles in hand cannot be rotated so every rotation that fits is given
+		if len(s.hands[s.turn]) > 0 {
+			for _, placement := range s.placements(s.turn) {
+				if placement.Slot > 0 {
+					targets = append(targets, &bg.BoardGameAction{
+						Team:        s.turn,
+						ActionType:  ActionPlaceTile,
+						MoreDetails: *placement,
+					})
+				}
+			}
+		}
 	} else {
 		// find all valid places to play token
 		targets = append(targets, &bg.BoardGameAction{
@@ -720,20 +1022,165 @@ func (s *state) targets() []*bg.BoardGameAction {
 	return targets
 }
 
-func (s *state) message() string {
-	message := fmt.Sprintf("%s must place a tile", s.turn)
-	if s.playTiles[s.turn] == nil {
-		message = fmt.Sprintf("%s must place a token", s.turn)
+// placements gets all valid places to play each tile in the team's hand in every distinct rotation
+func (s *state) placements(team string) []*PlaceTileActionDetails {
+	placements := make([]*PlaceTileActionDetails, 0)
+	if s.playTiles[team] == nil {
+		return placements
 	}
+	emptySpaces := s.board.getEmptySpaces()
+	hand := s.hand(team)
+	for slot, t := range hand {
+		// skip tiles identical to tiles in earlier slots
+		duplicate := false
+		for _, other := range hand[:slot] {
+			if other.equals(t) {
+				duplicate = true
+			}
+		}
+		if duplicate {
+			continue
+		}
+		rotated := t.copy()
+		rotations := make([]*tile, 0)
+		for i := 0; i < 4; i++ {
+			// skip rotations that are identical to previous rotations i.e. symmetric tiles
+			duplicate := false
+			for _, rotation := range rotations {
+				if sameSides(rotation, rotated) {
+					duplicate = true
+				}
+			}
+			if !duplicate {
+				rotations = append(rotations, rotated.copy())
+				for _, emptySpace := range emptySpaces {
+					if fits(emptySpace, rotated) {
+						placements = append(placements, &PlaceTileActionDetails{
+							X:    emptySpace.X,
+							Y:    emptySpace.Y,
+							Tile: tileToActionDetails(rotated),
+							Slot: slot,
+						})
+					}
+				}
+			}
+			rotated.RotateRight()
+		}
+	}
+	return placements
+}
+
+// moves gets all valid actions team can perform with tile placements covering every rotation of the play tile
+func (s *state) moves(team string) ([]*bg.BoardGameAction, error) {
 	if len(s.winners) > 0 {
-		message = fmt.Sprintf("%s tie", strings.Join(s.winners, ", "))
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("%s game already completed", key),
+			Status: bgerr.StatusGameOver,
+		}
+	}
+	if team != s.turn {
+		return nil, &bgerr.Error{
+			Err:    fmt.Errorf("currently %s's turn", s.turn),
+			Status: bgerr.StatusWrongTurn,
+		}
+	}
+	actions := make([]*bg.BoardGameAction, 0)
+	if s.playTiles[team] != nil {
+		for _, placement := range s.placements(team) {
+			actions = append(actions, &bg.BoardGameAction{
+				Team:        team,
+				ActionType:  ActionPlaceTile,
+				MoreDetails: *placement,
+			})
+		}
+		return actions, nil
+	}
+	for _, target := range s.targets() {
+		if target.ActionType == ActionPlaceToken {
+			actions = append(actions, target)
+		}
+	}
+	return actions, nil
+}
+
+// unseenTiles gets the tiles team has not seen i.e. the tiles in the deck and in the hands of other teams
+// this is found by removing all placed tiles, the revealed tiles, and the tiles in hands team can see from the full set of tiles
+func (s *state) unseenTiles(team string) []*tile {
+	seen := s.board.placedTiles()
+	seen = append(seen, s.hand(team)...)
+	seen = append(seen, s.revealed()...)
+	if s.publicHands {
+		for _, other := range s.teams {
+			if other != team {
+				seen = append(seen, s.hand(other)...)
+			}
+		}
+	}
+	amounts := tileCounts(seen)
+	unseen := make([]*tile, 0)
+	for i, tileAmount := range tiles {
+		for j := 0; j < amounts[i]; j++ {
+			unseen = append(unseen, tileAmount.tile.copy())
+		}
+	}
+	return unseen
+}
+
+// projectedScores gets the scores of all teams as if the game ended now
+func (s *state) projectedScores() (map[string]int, error) {
+	results, err := s.endGameScoring()
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[string]int, len(s.scores))
+	for team, score := range s.scores {
+		scores[team] = score
+	}
+	for _, result := range results {
+		for _, winner := range result.winners {
+			scores[winner] += result.points
+		}
+	}
+	return scores, nil
+}
+
+func (s *state) message() *Message {
+	message := newMessage(MessagePlaceTile, map[string]string{"Team": s.turn})
+	if s.playTiles[s.turn] == nil {
+		message = newMessage(MessagePlaceToken, map[string]string{"Team": s.turn})
+	}
+	if len(s.winners) > 0 && len(s.partnerships) > 0 {
+		// partners win together
+		winners := make([]string, 0)
+		for _, partnership := range s.partnerships {
+			if intersects(partnership, s.winners) {
+				winners = append(winners, strings.Join(partnership, " & "))
+			}
+		}
+		message = newMessage(MessageTie, map[string]string{"Teams": strings.Join(winners, ", ")})
+		if len(winners) == 1 {
+			message = newMessage(MessagePartnersWin, map[string]string{"Team": winners[0]})
+		}
+	} else if len(s.winners) > 0 {
+		message = newMessage(MessageTie, map[string]string{"Teams": strings.Join(s.winners, ", ")})
 		if len(s.winners) == 1 {
-			message = fmt.Sprintf("%s wins", s.winners[0])
+			message = newMessage(MessageWin, map[string]string{"Team": s.winners[0]})
 		}
 	}
 	return message
 }
 
+// owners gets the teams owning tokens in the order they are found
+func owners(tokens []*token) []string {
+	teams := make([]string, 0)
+	for _, token := range tokens {
+		if !contains(teams, token.Team) {
+			teams = append(teams, token.Team)
+		}
+	}
+	return teams
+}
+
 // get the tokens that fall in the structure
 func tokensInStructure(tokens []*token, structure *structure) []*token {
 	tokensInside := make([]*token, 0)
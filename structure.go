@@ -11,7 +11,10 @@ const (
 	NilStructure = "NilStructure"
 )
 
-var StructureTypeToTokenType = map[string]string{Farm: Farmer, City: Knight, Road: Thief, Cloister: Monk}
+var (
+	StructureTypeToTokenType = map[string]string{Farm: Farmer, City: Knight, Road: Thief, Cloister: Monk}
+	tokenTypeToStructureType = reverseMap(StructureTypeToTokenType)
+)
 
 // node that is part of a complete/incomplete City, Road, or Farm structure
 type node struct {
@@ -26,6 +29,23 @@ type structure struct {
 	nodes    []*node
 }
 
+// clone copies the structure pointing its nodes at the tiles in mapping
+// node sides are shared as they are never modified once the structure is generated
+func (s *structure) clone(mapping map[*tile]*tile) *structure {
+	nodes := make([]*node, len(s.nodes))
+	for i, n := range s.nodes {
+		nodes[i] = &node{
+			tile:  mapping[n.tile],
+			sides: n.sides,
+		}
+	}
+	return &structure{
+		typ:      s.typ,
+		complete: s.complete,
+		nodes:    nodes,
+	}
+}
+
 // get the score of a city structure by checking all city sections
 func scoreCity(city *structure) (int, error) {
 	if city.typ != City {
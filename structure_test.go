@@ -0,0 +1,37 @@
+package go_carcassonne
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GenerateRoad_DistinctCoordinates(t *testing.T) {
+	// a road running right from the start tile to 11,0 then up and back left to 1,10
+	// the tiles at 11,0 and 1,10 must be told apart even though their coordinates share the same digits
+	b := newBoard()
+	place := func(t2 *tile, x, y int) {
+		if err := b.Place(t2, x, y); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	for x := 1; x <= 10; x++ {
+		place(newTile(Farm, Road, Farm, Road, NilStructure, false, false), x, 0)
+	}
+	place(newTile(Road, Farm, Farm, Road, NilStructure, false, false), 11, 0)
+	for y := 1; y <= 9; y++ {
+		place(newTile(Road, Farm, Road, Farm, NilStructure, false, false), 11, y)
+	}
+	place(newTile(Farm, Farm, Road, Road, NilStructure, false, false), 11, 10)
+	for x := 10; x >= 1; x-- {
+		place(newTile(Farm, Road, Farm, Road, NilStructure, false, false), x, 10)
+	}
+	road, err := b.generateRoad(0, 0, SideRight)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, 32, len(road.nodes))
+	assert.False(t, road.complete)
+}
@@ -0,0 +1,52 @@
+package go_carcassonne
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+)
+
+// RenderSVG draws the current board and tokens of the game as an SVG image
+func (c *Carcassonne) RenderSVG(w io.Writer) error {
+	return writeSVG(w, newDrawing(c.state.teams, c.state.board.board, c.state.boardTokens))
+}
+
+// RenderSnapshotSVG draws the board and tokens of a snapshot as an SVG image where teams are colored in the order given
+func RenderSnapshotSVG(w io.Writer, teams []string, data *CarcassonneSnapshotData) error {
+	return writeSVG(w, newDrawing(teams, data.Board, data.BoardTokens))
+}
+
+func writeSVG(w io.Writer, d *drawing) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		svgNumber(d.width), svgNumber(d.height), svgNumber(d.width), svgNumber(d.height))
+	for _, s := range d.shapes {
+		if len(s.points) == 0 {
+			fmt.Fprintf(b, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n",
+				svgNumber(s.center.X), svgNumber(s.center.Y), svgNumber(s.radius), svgColor(s.fill))
+			continue
+		}
+		b.WriteString(`<polygon points="`)
+		for i, p := range s.points {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(svgNumber(p.X) + "," + svgNumber(p.Y))
+		}
+		fmt.Fprintf(b, `" fill="%s"/>`+"\n", svgColor(s.fill))
+	}
+	b.WriteString("</svg>\n")
+	return b.Flush()
+}
+
+// svgNumber formats a number with at most two decimal places
+func svgNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
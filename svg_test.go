@@ -0,0 +1,86 @@
+package go_carcassonne
+
+import (
+	"bytes"
+	"flag"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// golden compares got to the named file in testdata rewriting the file instead when -update is set
+func golden(t *testing.T, name string, got []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.True(t, bytes.Equal(want, got), "%s does not match, run go test -update to regenerate", path)
+}
+
+// playRandom plays up to actions random actions in a new seeded game
+func playRandom(t *testing.T, teams []string, seed int64, actions int) *Carcassonne {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       teams,
+		MoreOptions: CarcassonneMoreOptions{Seed: seed},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	random := rand.New(rand.NewSource(seed))
+	for i := 0; i < actions && len(carcassonne.state.winners) == 0; i++ {
+		if err := carcassonne.Do(randomAction(carcassonne.state, random)); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	return carcassonne
+}
+
+func Test_RenderSVG(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions int
+	}{
+		{name: "board_start.svg", actions: 0},
+		{name: "board_middle.svg", actions: 40},
+		{name: "board_end.svg", actions: 1000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			carcassonne := playRandom(t, []string{TeamA, TeamB, "TeamC"}, 7, test.actions)
+			var first, second bytes.Buffer
+			if err := carcassonne.RenderSVG(&first); err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			// rendering from a snapshot gives the same image
+			snapshot, err := carcassonne.GetSnapshot()
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			data := snapshot.MoreData.(CarcassonneSnapshotData)
+			if err := RenderSnapshotSVG(&second, snapshot.Teams, &data); err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			assert.Equal(t, first.String(), second.String())
+			golden(t, test.name, first.Bytes())
+		})
+	}
+}
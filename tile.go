@@ -52,6 +52,27 @@ func (t *tile) copy() *tile {
 	return newTile(t.Sides[SideTop], t.Sides[SideRight], t.Sides[SideBottom], t.Sides[SideLeft], t.Center, t.ConnectedCitySides, t.Banner)
 }
 
+// clone copies the tile including its location and team information but without its adjacent tiles
+// Sides is shared as rotating a tile replaces the map rather than modifying it
+func (t *tile) clone() *tile {
+	c := &tile{}
+	t.cloneInto(c)
+	return c
+}
+
+func (t *tile) cloneInto(c *tile) {
+	*c = *t
+	c.Teams = make(map[string][]string, len(t.Teams))
+	for side, teams := range t.Teams {
+		c.Teams[side] = teams
+	}
+	c.FarmTeams = make(map[string][]string, len(t.FarmTeams))
+	for farmSide, teams := range t.FarmTeams {
+		c.FarmTeams[farmSide] = teams
+	}
+	c.adjacent = make(map[string]*tile, len(t.adjacent))
+}
+
 func (t *tile) RotateRight() {
 	newSides := make(map[string]string)
 	for _, side := range Sides {
@@ -194,18 +215,50 @@ func (t *tile) connectedFarmSides(farmSide string) ([]string, error) {
 	return points, nil
 }
 
+// sameSides checks whether both tiles have the same sides in their current rotations
+func sameSides(t1, t2 *tile) bool {
+	for _, side := range Sides {
+		if t1.Sides[side] != t2.Sides[side] {
+			return false
+		}
+	}
+	return true
+}
+
+func tileToActionDetails(t *tile) TileActionDetails {
+	return TileActionDetails{
+		Top:                t.Sides[SideTop],
+		Right:              t.Sides[SideRight],
+		Bottom:             t.Sides[SideBottom],
+		Left:               t.Sides[SideLeft],
+		Center:             t.Center,
+		ConnectedCitySides: t.ConnectedCitySides,
+		Banner:             t.Banner,
+	}
+}
+
+// tile creates the tile described by the details
+func (d TileActionDetails) tile() *tile {
+	return newTile(d.Top, d.Right, d.Bottom, d.Left, d.Center, d.ConnectedCitySides, d.Banner)
+}
+
+// equals checks whether t2 is the same tile as t in some rotation
 func (t tile) equals(t2 *tile) bool {
+	if t.Banner != t2.Banner || t.ConnectedCitySides != t2.ConnectedCitySides || t.Center != t2.Center {
+		return false
+	}
+	// compare t rotated right i times without allocating the rotated sides
 	for i := 0; i < 4; i++ {
-		if t.Banner == t2.Banner &&
-			t.ConnectedCitySides == t2.ConnectedCitySides &&
-			t.Center == t2.Center &&
-			t.Sides[SideTop] == t2.Sides[SideTop] &&
-			t.Sides[SideRight] == t2.Sides[SideRight] &&
-			t.Sides[SideBottom] == t2.Sides[SideBottom] &&
-			t.Sides[SideLeft] == t2.Sides[SideLeft] {
+		same := true
+		for j, side := range Sides {
+			if t.Sides[side] != t2.Sides[Sides[(j+i)%len(Sides)]] {
+				same = false
+				break
+			}
+		}
+		if same {
 			return true
 		}
-		t.RotateRight()
 	}
 	return false
 }
@@ -0,0 +1,82 @@
+package go_carcassonne
+
+// RemainingTile is a type of tile with the number of tiles of that type not yet drawn
+type RemainingTile struct {
+	Tile  *tile
+	Count int
+}
+
+// OpenSpace is an empty space next to the board with the number of remaining tiles that fit there in some rotation
+type OpenSpace struct {
+	X, Y int
+	Fits int
+}
+
+// tileCounts gets the number of each type of tile in tiles left once the seen tiles are removed
+func tileCounts(seen []*tile) []int {
+	counts := make([]int, len(tiles))
+	for i, tileAmount := range tiles {
+		counts[i] = tileAmount.amount
+	}
+	for _, t := range seen {
+		for i, tileAmount := range tiles {
+			if counts[i] > 0 && tileAmount.tile.equals(t) {
+				counts[i]--
+				break
+			}
+		}
+	}
+	return counts
+}
+
+// remainingCounts gets the number of each type of tile not yet drawn as far as team can tell
+// tiles in hands team cannot see count as not drawn so hidden hands are never given away, no team sees every hand
+func (s *state) remainingCounts(team ...string) []int {
+	seen := s.board.placedTiles()
+	for _, t := range s.teams {
+		if len(team) == 0 || team[0] == t || s.publicHands {
+			seen = append(seen, s.hand(t)...)
+		}
+	}
+	return tileCounts(seen)
+}
+
+// remainingTiles gets the types of tile with at least one not yet drawn in the order of tiles
+func remainingTiles(counts []int) []*RemainingTile {
+	remaining := make([]*RemainingTile, 0)
+	for i, tileAmount := range tiles {
+		if counts[i] > 0 {
+			remaining = append(remaining, &RemainingTile{Tile: tileAmount.tile.copy(), Count: counts[i]})
+		}
+	}
+	return remaining
+}
+
+// fitCount gets the number of remaining tiles that fit emptySpace in some rotation
+func fitCount(emptySpace *tile, counts []int) int {
+	count := 0
+	for i, tileAmount := range tiles {
+		if counts[i] == 0 {
+			continue
+		}
+		copied := tileAmount.tile.copy()
+		for r := 0; r < 4; r++ {
+			if fits(emptySpace, copied) {
+				count += counts[i]
+				break
+			}
+			copied.RotateRight()
+		}
+	}
+	return count
+}
+
+// openSpaces gets every empty space on the board with the number of remaining tiles that fit there
+func (s *state) openSpaces(counts []int) []*OpenSpace {
+	emptySpaces := s.board.getEmptySpaces()
+	open := make([]*OpenSpace, 0, len(emptySpaces))
+	for _, emptySpace := range emptySpaces {
+		open = append(open, &OpenSpace{X: emptySpace.X, Y: emptySpace.Y, Fits: fitCount(emptySpace, counts)})
+	}
+	return open
+}
@@ -0,0 +1,70 @@
+package go_carcassonne
+
+import (
+	"math/rand"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RemainingTiles(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 11, HandSize: 2},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	random := rand.New(rand.NewSource(11))
+	for i := 0; i < 10; i++ {
+		if err := carcassonne.Do(randomAction(carcassonne.state, random)); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+
+	// spectators see every hand so the remaining tiles are exactly the deck
+	snapshot, _ := carcassonne.GetSnapshot()
+	data := snapshot.MoreData.(CarcassonneSnapshotData)
+	total := 0
+	for _, remaining := range data.RemainingTiles {
+		total += remaining.Count
+		count := 0
+		for _, t := range carcassonne.state.deck.tiles {
+			if t.equals(remaining.Tile) {
+				count++
+			}
+		}
+		assert.Equal(t, count, remaining.Count)
+	}
+	assert.Equal(t, len(carcassonne.state.deck.tiles), total)
+
+	// the number of fitting tiles matches trying every tile in the deck
+	assert.Len(t, data.OpenSpaces, len(carcassonne.state.board.getEmptySpaces()))
+	for i, emptySpace := range carcassonne.state.board.getEmptySpaces() {
+		fitting := 0
+		for _, t := range carcassonne.state.deck.tiles {
+			copied := t.copy()
+			for r := 0; r < 4; r++ {
+				if fits(emptySpace, copied) {
+					fitting++
+					break
+				}
+				copied.RotateRight()
+			}
+		}
+		assert.Equal(t, emptySpace.X, data.OpenSpaces[i].X)
+		assert.Equal(t, emptySpace.Y, data.OpenSpaces[i].Y)
+		assert.Equal(t, fitting, data.OpenSpaces[i].Fits)
+	}
+
+	// tiles in hidden hands still count as remaining
+	snapshot, _ = carcassonne.GetSnapshot(TeamA)
+	total = 0
+	for _, remaining := range snapshot.MoreData.(CarcassonneSnapshotData).RemainingTiles {
+		total += remaining.Count
+	}
+	assert.Equal(t, len(carcassonne.state.deck.tiles)+len(carcassonne.state.hand(TeamB)), total)
+}
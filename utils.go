@@ -36,3 +36,12 @@ func duplicates(list []string) bool {
 	}
 	return false
 }
+
+func intersects(a, b []string) bool {
+	for _, item := range a {
+		if contains(b, item) {
+			return true
+		}
+	}
+	return false
+}
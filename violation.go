@@ -0,0 +1,178 @@
+package go_carcassonne
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+)
+
+// Rule violation reasons
+const (
+	ReasonEdgeMismatch  = "EdgeMismatch"
+	ReasonDisconnected  = "Disconnected"
+	ReasonOccupied      = "Occupied"
+	ReasonClaimed       = "Claimed"
+	ReasonNoTokens      = "NoTokens"
+	ReasonWrongPhase    = "WrongPhase"
+	ReasonWrongTile     = "WrongTile"
+	ReasonWrongLocation = "WrongLocation"
+)
+
+// RuleViolation is an error explaining why a move breaks the rules so frontends can point out the cause
+// its error text is its message in English
+type RuleViolation interface {
+	error
+	Reason() string
+	Message() *Message
+}
+
+// AsRuleViolation gets the rule violation behind err if the move was refused for breaking the rules
+func AsRuleViolation(err error) (RuleViolation, bool) {
+	var gameErr *bgerr.Error
+	if errors.As(err, &gameErr) {
+		err = gameErr.Err
+	}
+	var violation RuleViolation
+	ok := errors.As(err, &violation)
+	return violation, ok
+}
+
+// EdgeMismatchError is a tile placed with a side that does not match the tile next to it
+type EdgeMismatchError struct {
+	X, Y                 int    // where the tile was placed
+	Side                 string // side of the placed tile that does not match
+	Structure            string // structure on the side of the placed tile
+	NeighborX, NeighborY int    // the tile next to the side
+	NeighborStructure    string // structure on the side of the neighbor facing the placed tile
+}
+
+func (e *EdgeMismatchError) Reason() string { return ReasonEdgeMismatch }
+
+func (e *EdgeMismatchError) Error() string { return e.Message().String() }
+
+func (e *EdgeMismatchError) Message() *Message {
+	return newMessage(MessageEdgeMismatch, map[string]string{
+		"X":                 strconv.Itoa(e.X),
+		"Y":                 strconv.Itoa(e.Y),
+		"Side":              e.Side,
+		"Structure":         e.Structure,
+		"NeighborX":         strconv.Itoa(e.NeighborX),
+		"NeighborY":         strconv.Itoa(e.NeighborY),
+		"NeighborStructure": e.NeighborStructure,
+	}, "Side", "Structure", "NeighborStructure")
+}
+
+// DisconnectedError is a tile placed where it touches no other tile
+type DisconnectedError struct {
+	X, Y int
+}
+
+func (e *DisconnectedError) Reason() string { return ReasonDisconnected }
+
+func (e *DisconnectedError) Error() string { return e.Message().String() }
+
+func (e *DisconnectedError) Message() *Message {
+	return newMessage(MessageDisconnected, map[string]string{"X": strconv.Itoa(e.X), "Y": strconv.Itoa(e.Y)})
+}
+
+// OccupiedError is a tile placed where there already is a tile
+type OccupiedError struct {
+	X, Y int
+}
+
+func (e *OccupiedError) Reason() string { return ReasonOccupied }
+
+func (e *OccupiedError) Error() string { return e.Message().String() }
+
+func (e *OccupiedError) Message() *Message {
+	return newMessage(MessageOccupied, map[string]string{"X": strconv.Itoa(e.X), "Y": strconv.Itoa(e.Y)})
+}
+
+// ClaimedError is a token placed in a structure that already has a token
+type ClaimedError struct {
+	X, Y      int
+	Side      string   // side or farm side the token was placed on
+	Structure string   // City, Road, or Farm
+	Owners    []string // teams with tokens in the structure
+}
+
+func (e *ClaimedError) Reason() string { return ReasonClaimed }
+
+func (e *ClaimedError) Error() string { return e.Message().String() }
+
+func (e *ClaimedError) Message() *Message {
+	return newMessage(MessageClaimed, map[string]string{
+		"X":         strconv.Itoa(e.X),
+		"Y":         strconv.Itoa(e.Y),
+		"Side":      e.Side,
+		"Structure": e.Structure,
+		"Owners":    strings.Join(e.Owners, ", "),
+	}, "Side", "Structure")
+}
+
+// NoTokensError is a token placed by a team with none left
+type NoTokensError struct {
+	Team string
+}
+
+func (e *NoTokensError) Reason() string { return ReasonNoTokens }
+
+func (e *NoTokensError) Error() string { return e.Message().String() }
+
+func (e *NoTokensError) Message() *Message {
+	return newMessage(MessageNoTokens, map[string]string{"Team": e.Team})
+}
+
+// WrongPhaseError is an action made during the wrong part of a turn e.g. placing a token before placing a tile
+type WrongPhaseError struct {
+	Team   string
+	Action string // the action type made
+	Phase  string // the action type expected, empty if team has nothing left to do
+}
+
+func (e *WrongPhaseError) Reason() string { return ReasonWrongPhase }
+
+func (e *WrongPhaseError) Error() string { return e.Message().String() }
+
+func (e *WrongPhaseError) Message() *Message {
+	if e.Phase == "" {
+		return newMessage(MessageNothingToDo, map[string]string{"Team": e.Team, "Action": e.Action}, "Action")
+	}
+	return newMessage(MessageWrongPhase, map[string]string{"Team": e.Team, "Action": e.Action, "Phase": e.Phase}, "Action", "Phase")
+}
+
+// WrongTileError is a tile placed that is not the tile team holds
+type WrongTileError struct {
+	Team string
+	Tile TileActionDetails // the tile that was placed
+}
+
+func (e *WrongTileError) Reason() string { return ReasonWrongTile }
+
+func (e *WrongTileError) Error() string { return e.Message().String() }
+
+func (e *WrongTileError) Message() *Message {
+	return newMessage(MessageWrongTile, map[string]string{"Team": e.Team, "Tile": fmt.Sprintf("%+v", e.Tile)})
+}
+
+// WrongLocationError is a token placed on a tile other than the tile just placed
+type WrongLocationError struct {
+	X, Y         int // where the token was placed
+	TileX, TileY int // the tile just placed
+}
+
+func (e *WrongLocationError) Reason() string { return ReasonWrongLocation }
+
+func (e *WrongLocationError) Error() string { return e.Message().String() }
+
+func (e *WrongLocationError) Message() *Message {
+	return newMessage(MessageWrongLocation, map[string]string{
+		"X":     strconv.Itoa(e.X),
+		"Y":     strconv.Itoa(e.Y),
+		"TileX": strconv.Itoa(e.TileX),
+		"TileY": strconv.Itoa(e.TileY),
+	})
+}
@@ -0,0 +1,132 @@
+package go_carcassonne
+
+import (
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/quibbble/go-boardgame/pkg/bgerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RuleViolations(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 21},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	violation := func(action *bg.BoardGameAction) RuleViolation {
+		err := carcassonne.Do(action)
+		found, ok := AsRuleViolation(err)
+		if !ok {
+			t.Errorf("expected rule violation but got %v", err)
+			t.FailNow()
+		}
+		return found
+	}
+	play := tileToActionDetails(carcassonne.state.playTiles[TeamA])
+	placeTile := func(x, y int, tile TileActionDetails) *bg.BoardGameAction {
+		return &bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceTile, MoreDetails: PlaceTileActionDetails{X: x, Y: y, Tile: tile}}
+	}
+
+	phase := violation(&bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceToken, MoreDetails: PlaceTokenActionDetails{Pass: true}})
+	assert.Equal(t, &WrongPhaseError{Team: TeamA, Action: ActionPlaceToken, Phase: ActionPlaceTile}, phase)
+
+	for _, tileAmount := range tiles {
+		if !tileAmount.tile.equals(carcassonne.state.playTiles[TeamA]) {
+			assert.Equal(t, ReasonWrongTile, violation(placeTile(1, 0, tileToActionDetails(tileAmount.tile))).Reason())
+			break
+		}
+	}
+	assert.Equal(t, &OccupiedError{X: 0, Y: 0}, violation(placeTile(0, 0, play)))
+	assert.Equal(t, &DisconnectedError{X: 5, Y: 5}, violation(placeTile(5, 5, play)))
+
+	// rotate the play tile around the start tile until a side does not match
+	var mismatch *EdgeMismatchError
+	for _, location := range [][2]int{{0, 1}, {1, 0}, {0, -1}, {-1, 0}} {
+		rotated := carcassonne.state.playTiles[TeamA].copy()
+		for r := 0; r < 4 && mismatch == nil; r++ {
+			err := carcassonne.Do(placeTile(location[0], location[1], tileToActionDetails(rotated)))
+			if found, ok := AsRuleViolation(err); ok {
+				mismatch, _ = found.(*EdgeMismatchError)
+			} else if err == nil {
+				t.Error("expected tile placement to fail")
+				t.FailNow()
+			}
+			rotated.RotateRight()
+		}
+		if mismatch != nil {
+			break
+		}
+	}
+	if mismatch == nil {
+		t.Error("no edge mismatch found")
+		t.FailNow()
+	}
+	assert.Equal(t, 0, mismatch.NeighborX)
+	assert.Equal(t, 0, mismatch.NeighborY)
+	assert.Equal(t, startTile.Sides[AcrossSide[mismatch.Side]], mismatch.NeighborStructure)
+	assert.NotEqual(t, mismatch.Structure, mismatch.NeighborStructure)
+	assert.Equal(t, bgerr.StatusInvalidAction, carcassonne.Do(placeTile(mismatch.X, mismatch.Y, play)).(*bgerr.Error).Status)
+}
+
+func Test_RuleViolations_Tokens(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 21},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// place a tile that leaves somewhere to place a token
+	var token *PlaceTokenActionDetails
+	for _, placement := range carcassonne.state.placements(TeamA) {
+		clone := carcassonne.Clone()
+		if err := clone.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceTile, MoreDetails: *placement}); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		for _, target := range clone.state.targets() {
+			if details, ok := target.MoreDetails.(PlaceTokenActionDetails); ok && !details.Pass && details.Type != Monk {
+				token = &details
+				break
+			}
+		}
+		if token != nil {
+			carcassonne = clone
+			break
+		}
+	}
+	if token == nil {
+		t.Error("no token placement found")
+		t.FailNow()
+	}
+	placeToken := func(details PlaceTokenActionDetails) RuleViolation {
+		found, ok := AsRuleViolation(carcassonne.Do(&bg.BoardGameAction{Team: TeamA, ActionType: ActionPlaceToken, MoreDetails: details}))
+		if !ok {
+			t.Error("expected rule violation")
+			t.FailNow()
+		}
+		return found
+	}
+
+	wrong := *token
+	wrong.X += 3
+	assert.Equal(t, &WrongLocationError{X: wrong.X, Y: wrong.Y, TileX: token.X, TileY: token.Y}, placeToken(wrong))
+
+	carcassonne.state.tokens[TeamA] = 0
+	assert.Equal(t, &NoTokensError{Team: TeamA}, placeToken(*token))
+	carcassonne.state.tokens[TeamA] = 7
+
+	// another team already has a token in the structure
+	carcassonne.state.boardTokens = append(carcassonne.state.boardTokens, newToken(token.X, token.Y, TeamB, token.Type, token.Side))
+	claimed := placeToken(*token).(*ClaimedError)
+	assert.Equal(t, ReasonClaimed, claimed.Reason())
+	assert.Equal(t, []string{TeamB}, claimed.Owners)
+	assert.Equal(t, tokenTypeToStructureType[token.Type], claimed.Structure)
+	assert.Equal(t, token.Side, claimed.Side)
+
+}
@@ -0,0 +1,105 @@
+package go_carcassonne
+
+import (
+	"math/rand"
+	"testing"
+
+	bg "github.com/quibbble/go-boardgame"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Visibility(t *testing.T) {
+	for _, visibility := range []string{"", VisibilityPrivate, VisibilityPublic} {
+		t.Run(visibility, func(t *testing.T) {
+			carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+				Teams:       []string{TeamA, TeamB},
+				MoreOptions: CarcassonneMoreOptions{Seed: 9, HandSize: 2, Visibility: visibility},
+			})
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			snapshot, _ := carcassonne.GetSnapshot(TeamA)
+			data := snapshot.MoreData.(CarcassonneSnapshotData)
+			assert.Equal(t, carcassonne.state.playTiles[TeamA], data.PlayTile)
+			if visibility == VisibilityPublic {
+				assert.Equal(t, carcassonne.state.hand(TeamB), data.Hands[TeamB])
+				assert.Len(t, carcassonne.state.unseenTiles(TeamA), len(carcassonne.state.deck.tiles))
+			} else {
+				assert.Nil(t, data.Hands)
+				assert.Len(t, carcassonne.state.unseenTiles(TeamA), len(carcassonne.state.deck.tiles)+2)
+			}
+
+			// spectators see every hand
+			snapshot, _ = carcassonne.GetSnapshot()
+			data = snapshot.MoreData.(CarcassonneSnapshotData)
+			assert.Len(t, data.Hands, 2)
+			assert.Len(t, data.Hands[TeamA], 2)
+			assert.Len(t, data.Hands[TeamB], 2)
+		})
+	}
+}
+
+func Test_Visibility_Reveal(t *testing.T) {
+	carcassonne, err := NewCarcassonne(&bg.BoardGameOptions{
+		Teams:       []string{TeamA, TeamB},
+		MoreOptions: CarcassonneMoreOptions{Seed: 9, Reveal: 3},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	snapshot, _ := carcassonne.GetSnapshot(TeamB)
+	revealed := snapshot.MoreData.(CarcassonneSnapshotData).Revealed
+	assert.Len(t, revealed, 3)
+	assert.Len(t, carcassonne.state.unseenTiles(TeamA), len(carcassonne.state.deck.tiles)-3+1)
+
+	// the first revealed tile is the next one drawn
+	playTurn(t, carcassonne)
+	assert.True(t, carcassonne.state.playTiles[TeamA].equals(revealed[0]))
+	snapshot, _ = carcassonne.GetSnapshot(TeamB)
+	assert.Equal(t, revealed[1:], snapshot.MoreData.(CarcassonneSnapshotData).Revealed[:2])
+
+	game := carcassonne.GetBGN()
+	assert.Equal(t, "3", game.Tags["Reveal"])
+	builder := Builder{}
+	loaded, err := builder.Load(game)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	assert.Equal(t, 3, loaded.(*Carcassonne).state.reveal)
+
+	// bots keep the revealed tiles on top of the deck
+	determinized := (&mctsSearch{game: carcassonne, team: TeamB, unseen: carcassonne.state.unseenTiles(TeamB), random: rand.New(rand.NewSource(1))}).determinize()
+	assert.Equal(t, carcassonne.state.revealed(), determinized.state.revealed())
+}
+
+func Test_Visibility_RevealRedraw(t *testing.T) {
+	s := newState([]string{TeamA, TeamB}, 9, nil)
+	s.reveal = 3
+	s.playTiles[TeamA] = nil
+	s.playTiles[TeamB] = newTile("Lake", "Lake", "Lake", "Lake", NilStructure, false, false)
+	revealed := s.revealed()
+	if err := s.nextTurn(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// the unplayable tile is redrawn from the top of the deck and shuffled back in below the revealed tiles
+	assert.True(t, s.playTiles[TeamB].equals(revealed[0]))
+	assert.Equal(t, revealed[1:], s.revealed()[:2])
+	for _, top := range s.revealed() {
+		assert.NotEqual(t, "Lake", top.Sides[SideTop])
+	}
+}
+
+func Test_Visibility_Invalid(t *testing.T) {
+	tests := []CarcassonneMoreOptions{
+		{Visibility: "Hidden"},
+		{Reveal: -1},
+	}
+	for _, test := range tests {
+		_, err := NewCarcassonne(&bg.BoardGameOptions{Teams: []string{TeamA, TeamB}, MoreOptions: test})
+		assert.Error(t, err)
+	}
+}